@@ -0,0 +1,105 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// stopWords are left out of the most common words.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "its": true, "of": true, "on": true, "or": true, "that": true,
+	"the": true, "this": true, "to": true, "was": true, "with": true,
+	"you": true, "your": true,
+}
+
+type count struct {
+	text string
+	n    int
+}
+
+// topCounts returns the n most common entries, most common first.
+func topCounts(counts map[string]int, n int) []count {
+	var cs []count
+	for t, c := range counts {
+		cs = append(cs, count{t, c})
+	}
+	slices.SortFunc(cs, func(a, b count) int {
+		if c := cmp.Compare(b.n, a.n); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.text, b.text)
+	})
+	return cs[:min(n, len(cs))]
+}
+
+// runAnalytics shows the most common answers, and words in answers and
+// clues, across the cached puzzles that have been played. It only uses
+// local data. With lock (spoiler_lock), the answers to puzzles that
+// haven't been solved are only included if the user confirms it.
+func runAnalytics(w io.Writer, s *store, top int, lock bool) error {
+	var puzzles []puzzledata
+	var unsolved int
+	for _, p := range s.cachedPuzzles() {
+		if _, ok := s.game(p.PuzzleDate); !ok && len(s.attempts(p.PuzzleDate)) == 0 {
+			continue
+		}
+		puzzles = append(puzzles, p)
+		if !s.solved(p.PuzzleDate) {
+			unsolved++
+		}
+	}
+	if lock && unsolved > 0 && !confirm(fmt.Sprintf("%d of the puzzles you've played aren't solved yet. Include their answers?", unsolved), false) {
+		puzzles = slices.DeleteFunc(puzzles, func(p puzzledata) bool { return !s.solved(p.PuzzleDate) })
+	}
+
+	answers := make(map[string]int)
+	answerWords := make(map[string]int)
+	clueWords := make(map[string]int)
+	var played int
+	for _, p := range puzzles {
+		played++
+		for q, a := range p.Solutions {
+			a = normalizeAnswer(a, false)
+			answers[a]++
+			for _, w := range strings.Fields(a) {
+				if !stopWords[w] {
+					answerWords[w]++
+				}
+			}
+
+			for _, w := range strings.Fields(normalizeAnswer(q, false)) {
+				w = strings.Trim(w, `.,;:!?"'()`)
+				if w != "" && !stopWords[w] {
+					clueWords[w]++
+				}
+			}
+		}
+	}
+	if played == 0 {
+		fmt.Fprintln(w, "No puzzles played yet")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Across %d puzzles played:\n", played)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, sec := range []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"Most common answers", answers},
+		{"Most common words in answers", answerWords},
+		{"Most common words in clues", clueWords},
+	} {
+		fmt.Fprintf(tw, "\n%s\n", sec.title)
+		for i, c := range topCounts(sec.counts, top) {
+			fmt.Fprintf(tw, "%3d.\t%s\t%d\n", i+1, c.text, c.n)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeAnswer puts a guess or an answer into a canonical form for
+// comparison: NFC normalized, case folded, and with runs of whitespace
+// collapsed. With ignoreAccents, diacritics are removed too (so "cafe"
+// matches "café").
+func normalizeAnswer(s string, ignoreAccents bool) string {
+	s = norm.NFC.String(s)
+	if ignoreAccents {
+		t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		if r, _, err := transform.String(t, s); err == nil {
+			s = r
+		}
+	}
+	s = cases.Fold().String(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// answersMatch reports whether a guess matches an answer.
+func answersMatch(guess, answer string, ignoreAccents bool) bool {
+	return normalizeAnswer(guess, ignoreAccents) == normalizeAnswer(answer, ignoreAccents)
+}
+
+// countLetters returns the number of letters in the runes.
+func countLetters(rs []rune) int {
+	var n int
+	for _, r := range rs {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
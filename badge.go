@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// badgeCharWidth is roughly how wide a character is in the badge's
+// 11px Verdana, as the SVG can't measure its own text.
+const badgeCharWidth = 7
+
+// badgeSVG draws a badge in the flat style of shields.io, with the label
+// on grey on the left and the value on color on the right.
+func badgeSVG(label, value, color string) string {
+	lw := utf8.RuneCountInString(label)*badgeCharWidth + 10
+	vw := utf8.RuneCountInString(value)*badgeCharWidth + 10
+	label, value = html.EscapeString(label), html.EscapeString(value)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+  <title>%[4]s: %[5]s</title>
+  <linearGradient id="s" x2="0" y2="100%%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%[2]d" height="20" fill="#555"/>
+    <rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>
+    <rect width="%[1]d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text>
+    <text x="%[7]d" y="14">%[4]s</text>
+    <text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text>
+    <text x="%[8]d" y="14">%[5]s</text>
+  </g>
+</svg>
+`, lw+vw, lw, vw, label, value, color, lw/2, lw+vw/2)
+}
+
+// streakBadge is a badge of the current streak, e.g. for a profile
+// README: green while there is one, grey otherwise.
+func streakBadge(s *store, now time.Time) string {
+	n := currentStreak(s, now)
+	color := "#4c1"
+	if n == 0 {
+		color = "#9f9f9f"
+	}
+	return badgeSVG("Bracket City streak", fmt.Sprint(n), color)
+}
+
+// updateBadge writes the streak badge to the configured path, if there
+// is one, once a puzzle is solved. It's drawn straight away, while the
+// store can be read, and written in the background.
+func (m model) updateBadge() tea.Cmd {
+	path := expandHome(m.cfg.BadgePath)
+	if path == "" || m.store == nil || m.warmup || noSave || m.host != nil {
+		return nil
+	}
+	svg := streakBadge(m.store, time.Now())
+	return func() tea.Msg {
+		if err := os.WriteFile(path, []byte(svg), 0o644); err != nil {
+			slog.Error("failed to write the badge", "path", path, "err", err)
+			return nil
+		}
+		slog.Debug("wrote badge", "path", path)
+		return nil
+	}
+}
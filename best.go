@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// personalBest is the best finished attempt at a puzzle: the fastest
+// time and the fewest incorrect guesses, which may come from
+// different attempts.
+type personalBest struct {
+	ElapsedSeconds int64
+	Incorrect      int
+}
+
+// bestOf finds the personal best among games, ignoring any that
+// weren't solved. Games from before the timer existed have no time,
+// so they only count towards the fewest incorrect guesses.
+func bestOf(games []gamestate) (personalBest, bool) {
+	var pb personalBest
+	found := false
+	for _, gs := range games {
+		if !gs.Done || gs.GaveUp {
+			continue
+		}
+		if !found || gs.Incorrect < pb.Incorrect {
+			pb.Incorrect = gs.Incorrect
+		}
+		if gs.ElapsedSeconds > 0 && (pb.ElapsedSeconds == 0 || gs.ElapsedSeconds < pb.ElapsedSeconds) {
+			pb.ElapsedSeconds = gs.ElapsedSeconds
+		}
+		found = true
+	}
+	return pb, found
+}
+
+// beats reports whether a finished game improves on the personal best.
+func (pb personalBest) beats(gs gamestate) bool {
+	if gs.Incorrect < pb.Incorrect {
+		return true
+	}
+	return gs.ElapsedSeconds > 0 && pb.ElapsedSeconds > 0 && gs.ElapsedSeconds < pb.ElapsedSeconds
+}
+
+// runAttempts lists the archived attempts at the puzzle for a date,
+// and the current one, marking the personal bests. The puzzle's
+// completion text follows, if it's been solved (or spoilers is set).
+func runAttempts(w io.Writer, s *store, d time.Time, spoilers bool) error {
+	date := d.Format(time.DateOnly)
+	games := s.attempts(date)
+	if gs, ok := s.game(date); ok {
+		games = append(games, gs)
+	}
+	if len(games) == 0 {
+		fmt.Fprintf(w, "No attempts at the puzzle for %s\n", date)
+		return nil
+	}
+	pb, _ := bestOf(games)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	p, _ := s.puzzle(date)
+	fmt.Fprintln(tw, "ATTEMPT\tTIME\tINCORRECT\tLETTERS\tSOLVED\tSCORE\tPB")
+	for i, gs := range games {
+		name := fmt.Sprint(i + 1)
+		if i == len(games)-1 && len(games) > len(s.attempts(date)) {
+			name += " (current)"
+		}
+		t := "-"
+		if gs.ElapsedSeconds > 0 {
+			t = formatElapsed(time.Duration(gs.ElapsedSeconds) * time.Second)
+		}
+		solved := "no"
+		switch {
+		case gs.GaveUp:
+			solved = "gave up"
+		case gs.Done:
+			solved = "yes"
+		}
+
+		// Which bests does this attempt hold?
+		var best string
+		if gs.Done && !gs.GaveUp {
+			switch {
+			case gs.ElapsedSeconds > 0 && gs.ElapsedSeconds == pb.ElapsedSeconds && gs.Incorrect == pb.Incorrect:
+				best = "time, incorrect"
+			case gs.ElapsedSeconds > 0 && gs.ElapsedSeconds == pb.ElapsedSeconds:
+				best = "time"
+			case gs.Incorrect == pb.Incorrect:
+				best = "incorrect"
+			}
+		}
+		sc := "-"
+		if gs.Done && len(p.Solutions) > 0 {
+			sc = fmt.Sprint(score(gs, len(p.Solutions), profilePenalties(s)))
+			if gs.Penalties != nil {
+				sc += " (" + gs.Penalties.Preset + ")"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n", name, t, gs.Incorrect, gs.Chars, solved, sc, best)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Show the completion text, unless it would be a spoiler
+	if p.CompletionText == "" {
+		return nil
+	}
+	_, solvedOnce := bestOf(games)
+	if !solvedOnce && !spoilers {
+		fmt.Fprintln(w, "\nYou haven't solved this puzzle yet, so its completion text is hidden (show it with --spoilers).")
+		return nil
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, renderCompletionText(p.CompletionText, maxBodyWidth))
+	return nil
+}
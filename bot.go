@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// channelGames plays a game per chat channel for the bots, each in a
+// profile of its own (e.g. discord-1234), so a channel's games and
+// stats are kept apart from yours and every other channel's.
+type channelGames struct {
+	prefix string
+	cfg    config
+
+	mu      sync.Mutex
+	engines map[string]*engine
+}
+
+func newChannelGames(prefix string, cfg config) *channelGames {
+	// A channel's solves are its own, not yours to post anywhere
+	cfg.WebhookURL, cfg.BadgePath = "", ""
+	return &channelGames{
+		prefix:  prefix,
+		cfg:     cfg,
+		engines: make(map[string]*engine),
+	}
+}
+
+// engine returns the engine for a channel's games, opening its store
+// the first time.
+func (c *channelGames) engine(channel string) (*engine, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.engines[channel]; ok {
+		return e, nil
+	}
+
+	cfg := c.cfg
+	cfg.Profile = c.prefix + "-" + channel
+	if err := checkProfile(cfg.Profile); err != nil {
+		return nil, err
+	}
+	path, err := storePath(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s, err := openStore(path)
+	if err != nil {
+		return nil, err
+	}
+	e := newEngine(s, cfg)
+	c.engines[channel] = e
+	return e, nil
+}
+
+// chatGame formats a game's state for a chat message, in the Markdown
+// that Discord and Slack both understand.
+func chatGame(g gameReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "> %s\n", g.Puzzle)
+	switch {
+	case g.GaveUp:
+		fmt.Fprintf(&b, "Gave up on the puzzle for %s.", g.Date)
+	case g.Done:
+		fmt.Fprintf(&b, "🎉 Solved the puzzle for %s, with %d incorrect!", g.Date, g.Incorrect)
+	default:
+		fmt.Fprintf(&b, "%s: %d/%d solved, %d incorrect", g.Date, g.Correct, g.Total, g.Incorrect)
+	}
+	return b.String()
+}
+
+// chatGuess formats the result of someone's guess for a chat message.
+func chatGuess(who string, r guessResult) string {
+	var line string
+	switch {
+	case r.Message != "":
+		line = fmt.Sprintf("%s guessed %q: %s", who, r.Guess, r.Message)
+	case r.Right:
+		line = fmt.Sprintf("✓ %s got %q!", who, r.Guess)
+	default:
+		line = fmt.Sprintf("✗ %s guessed %q, which is wrong", who, r.Guess)
+	}
+	return line + "\n" + chatGame(r.gameReport)
+}
+
+// chatError explains why a guess from a chat couldn't be made.
+func chatError(err error) string {
+	var ce coolingError
+	switch {
+	case errors.Is(err, errPuzzleFinished):
+		return "Today's puzzle is already finished, come back tomorrow!"
+	case errors.As(err, &ce):
+		return fmt.Sprintf("Hard mode: wait %s after a wrong guess", ce.left.Round(time.Second))
+	}
+	return err.Error()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// broadcastState is the read-only view of a game sent to spectators.
+// It doesn't include any answers that haven't been found yet.
+type broadcastState struct {
+	Date      string `json:"date"`
+	Puzzle    string `json:"puzzle"`
+	Correct   int    `json:"correct"`
+	Total     int    `json:"total"`
+	Incorrect int    `json:"incorrect"`
+	Chars     int    `json:"chars"`
+	Done      bool   `json:"done"`
+}
+
+// broadcaster serves the live state of a game over HTTP, so others
+// can watch along: a page at /, the current state as JSON at /state,
+// and a stream of updates as server-sent events at /events.
+type broadcaster struct {
+	addr string
+
+	mu   sync.Mutex
+	last []byte
+	subs map[chan []byte]struct{}
+}
+
+// startBroadcast starts serving on addr (e.g. ":8642") in the
+// background. The returned function stops the server.
+func startBroadcast(addr string) (*broadcaster, func() error, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &broadcaster{
+		addr: ln.Addr().String(),
+		subs: make(map[chan []byte]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", b.handlePage)
+	mux.HandleFunc("GET /state", b.handleState)
+	mux.HandleFunc("GET /events", b.handleEvents)
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("broadcast server failed", "err", err)
+		}
+	}()
+	slog.Info("broadcasting", "addr", b.addr)
+	return b, srv.Close, nil
+}
+
+// url is the address spectators can open to watch.
+func (b *broadcaster) url() string {
+	host, port, err := net.SplitHostPort(b.addr)
+	if err != nil {
+		return "http://" + b.addr
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// publish sends the latest state to all spectators.
+func (b *broadcaster) publish(s broadcastState) {
+	msg, err := json.Marshal(s)
+	if err != nil {
+		slog.Error("failed to encode broadcast state", "err", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last = msg
+	for ch := range b.subs {
+		// Drop the update rather than block on a slow spectator,
+		// the next one replaces it anyway
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+func (b *broadcaster) subscribe() chan []byte {
+	ch := make(chan []byte, 1)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[ch] = struct{}{}
+	if b.last != nil {
+		ch <- b.last
+	}
+	return ch
+}
+
+func (b *broadcaster) unsubscribe(ch chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, ch)
+}
+
+func (b *broadcaster) handleState(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	msg := b.last
+	b.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if msg == nil {
+		msg = []byte("null")
+	}
+	w.Write(msg)
+}
+
+func (b *broadcaster) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-ch:
+			fmt.Fprintf(w, "data: %s\n\n", msg)
+			flusher.Flush()
+		}
+	}
+}
+
+func (b *broadcaster) handlePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, broadcastPage)
+}
+
+// broadcastPage is a minimal spectator page, suitable for a browser or
+// an OBS browser source.
+const broadcastPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>brack</title>
+<style>
+  body { font-family: monospace; background: #1a1a1a; color: #eee; max-width: 60em; margin: 2em auto; font-size: 1.2em; }
+  .q { color: #0f0f0f; background: #e8c566; }
+  .done { color: #8fd18f; }
+</style>
+</head>
+<body>
+<h3 id="header">[ Bracket City ]</h3>
+<div id="score"></div>
+<p id="puzzle">Waiting for the game to start...</p>
+<div id="status"></div>
+<script>
+const esc = (s) => s.replace(/[&<>"]/g, (c) => ({"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;"}[c]));
+new EventSource("/events").onmessage = (e) => {
+  const s = JSON.parse(e.data);
+  document.getElementById("header").textContent = "[ Bracket City | " + s.date + " ]";
+  document.getElementById("score").textContent =
+    "✅ " + s.correct + "/" + s.total + " ❌ " + s.incorrect + " ⌨️ " + s.chars;
+  document.getElementById("puzzle").innerHTML =
+    esc(s.puzzle).replace(/\[[^\[\]]+\]/g, (q) => '<span class="q">' + q + "</span>");
+  document.getElementById("status").innerHTML = s.done ? '<span class="done">🎉 Solved! 🎉</span>' : "";
+};
+</script>
+</body>
+</html>
+`
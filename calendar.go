@@ -0,0 +1,478 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var _ tea.Model = calendarModel{}
+
+// Styles for a day's status on the calendar. Solved days are shaded by
+// how many incorrect guesses it took, like a contribution graph.
+var (
+	unplayedStyle   = lipgloss.NewStyle().Faint(true)
+	inProgressStyle = lipgloss.NewStyle().Foreground(colorYellow)
+	gaveUpStyle     = lipgloss.NewStyle().Foreground(colorRed)
+	failedStyle     = lipgloss.NewStyle().Foreground(colorRed).Underline(true)
+	solvedStyles    = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(colorGreens[0]), // no incorrect guesses
+		lipgloss.NewStyle().Foreground(colorGreens[1]), // a few
+		lipgloss.NewStyle().Foreground(colorGreens[2]), // lots
+	}
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// calendarModel browses past puzzles: a year at a glance, like a
+// contribution graph, which zooms into a month. Choosing a day plays
+// its puzzle.
+type calendarModel struct {
+	store *store
+	today time.Time
+	sel   time.Time
+	month bool
+	w, h  int
+
+	// hideLegend collapses the legend to a single help line.
+	hideLegend bool
+
+	// chosen is set when the selected day should be played. Beside
+	// the game, the game loads it. Otherwise the calendar does, using
+	// cfg for the game, with status saying how it's going.
+	chosen bool
+	cfg    config
+	status string
+
+	// pane is set when the calendar is shown beside the game, rather
+	// than on its own. stacked is set when it was opened over another
+	// view, which closing it goes back to.
+	pane    bool
+	stacked bool
+
+	// summaries caches the counts for each month (by "2006-01") shown
+	// so far, which are loaded when a month is first shown.
+	summaries map[string]monthCounts
+
+	// failed holds why each day's puzzle (by date) couldn't be loaded,
+	// until it's retried.
+	failed map[string]error
+}
+
+// monthCounts is how many of a month's puzzles were played and solved.
+type monthCounts struct {
+	played, solved int
+}
+
+// monthSummaryMsg is the result of counting a month's games.
+type monthSummaryMsg struct {
+	month  string
+	counts monthCounts
+}
+
+func newCalendarModel(s *store, today time.Time) calendarModel {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	return calendarModel{
+		store:     s,
+		today:     today,
+		sel:       today,
+		summaries: make(map[string]monthCounts),
+		failed:    make(map[string]error),
+	}
+}
+
+// summarize loads the counts for the selected month, if it's showing
+// and they aren't loaded yet.
+func (m calendarModel) summarize() tea.Cmd {
+	month := m.sel.Format("2006-01")
+	if _, ok := m.summaries[month]; ok || !m.month || m.pane {
+		return nil
+	}
+	s := m.store
+	return func() tea.Msg {
+		played, solved := s.monthSummary(month)
+		return monthSummaryMsg{month: month, counts: monthCounts{played, solved}}
+	}
+}
+
+// summary returns the counts for a month, if they're loaded. Beside
+// the game, they're counted as they're shown, so they keep up with it.
+func (m calendarModel) summary(month string) (monthCounts, bool) {
+	if m.pane {
+		played, solved := m.store.monthSummary(month)
+		return monthCounts{played, solved}, true
+	}
+	c, ok := m.summaries[month]
+	return c, ok
+}
+
+// dayStyle styles a day by how the game on it went.
+func (m calendarModel) dayStyle(day time.Time) lipgloss.Style {
+	date := day.Format(time.DateOnly)
+	if _, failed := m.failed[date]; failed {
+		return failedStyle
+	}
+	gs, ok := m.store.game(date)
+	switch {
+	case !ok:
+		return unplayedStyle
+	case gs.GaveUp:
+		return gaveUpStyle
+	case !gs.Done:
+		return inProgressStyle
+	case gs.Incorrect == 0:
+		return solvedStyles[0]
+	case gs.Incorrect <= 3:
+		return solvedStyles[1]
+	}
+	return solvedStyles[2]
+}
+
+// move changes the selected day, without going past today.
+func (m calendarModel) move(days int) calendarModel {
+	sel := m.sel.AddDate(0, 0, days)
+	if sel.After(m.today) {
+		sel = m.today
+	}
+	m.sel = sel
+	return m
+}
+
+func (m calendarModel) Init() tea.Cmd {
+	return m.summarize()
+}
+
+func (m calendarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w, m.h = msg.Width, msg.Height
+
+	case monthSummaryMsg:
+		m.summaries[msg.month] = msg.counts
+
+	case puzzleLoadedMsg:
+		m.status = ""
+		m.failed[msg.date.Format(time.DateOnly)] = msg.err
+
+	case shownMsg:
+		// Games may have been played since the counts were loaded
+		m.status = ""
+		clear(m.summaries)
+		return m, m.summarize()
+
+	case tea.KeyMsg:
+		m.status = ""
+		m, cmd := m.updateKey(msg)
+		return m, tea.Batch(cmd, m.summarize())
+	}
+	return m, nil
+}
+
+// updateKey handles a key press.
+func (m calendarModel) updateKey(msg tea.KeyMsg) (calendarModel, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, keys.Close) && !m.month:
+		if m.stacked {
+			return m, popView
+		}
+		return m, tea.Quit
+	case key.Matches(msg, keys.Left):
+		// In the year view, days run down the columns
+		if m.month {
+			return m.move(-1), nil
+		}
+		return m.move(-7), nil
+	case key.Matches(msg, keys.Right):
+		if m.month {
+			return m.move(1), nil
+		}
+		return m.move(7), nil
+	case key.Matches(msg, keys.Up):
+		if m.month {
+			return m.move(-7), nil
+		}
+		return m.move(-1), nil
+	case key.Matches(msg, keys.Down):
+		if m.month {
+			return m.move(7), nil
+		}
+		return m.move(1), nil
+	case key.Matches(msg, keys.Zoom):
+		m.month = !m.month
+	case key.Matches(msg, keys.Legend):
+		m.hideLegend = !m.hideLegend
+	case key.Matches(msg, keys.Close):
+		m.month = false
+	case key.Matches(msg, keys.Today):
+		m.sel = m.today
+		return m.choose()
+	case key.Matches(msg, keys.Retry) && m.failed[m.sel.Format(time.DateOnly)] != nil:
+		return m.choose()
+	case key.Matches(msg, keys.Submit):
+		if !m.month {
+			m.month = true
+			return m, nil
+		}
+		return m.choose()
+	}
+	return m, nil
+}
+
+// choose plays the selected day's puzzle, trying again if it couldn't
+// be loaded before.
+func (m calendarModel) choose() (calendarModel, tea.Cmd) {
+	delete(m.failed, m.sel.Format(time.DateOnly))
+	if m.pane {
+		m.chosen = true
+		return m, nil
+	}
+	m.status = tr("Loading the puzzle for %s...", m.sel.Format(time.DateOnly))
+	return m, m.play()
+}
+
+func (m calendarModel) View() string {
+	if m.month {
+		return m.monthView()
+	}
+	return m.yearView()
+}
+
+// weekStart returns the Monday of the week a day is in.
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// yearView shows the year of the selected day, a column per week.
+func (m calendarModel) yearView() string {
+	year := m.sel.Year()
+	first := weekStart(time.Date(year, 1, 1, 0, 0, 0, 0, time.Local))
+	last := time.Date(year, 12, 31, 0, 0, 0, 0, time.Local)
+	weeks := int(last.Sub(first).Hours()/24)/7 + 1
+
+	// Only show as many weeks as fit, keeping the selection in view
+	from := 0
+	if m.w > 0 {
+		fit := max((m.w-4)/2, 1)
+		if weeks > fit {
+			selWeek := int(weekStart(m.sel).Sub(first).Hours()/24) / 7
+			from = min(max(selWeek-fit/2, 0), weeks-fit)
+			weeks = fit
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("[ Bracket City | %d ]", year)) + "\n\n")
+
+	// Month labels above the first week of each month
+	var labels strings.Builder
+	labels.WriteString("    ")
+	for wk := from; wk < from+weeks; wk++ {
+		start := first.AddDate(0, 0, 7*wk)
+		var label string
+		for d := range 7 {
+			if day := start.AddDate(0, 0, d); day.Day() == 1 && day.Year() == year {
+				label = day.Format("Jan")
+			}
+		}
+		if label != "" && labels.Len() <= 4+2*(wk-from) {
+			labels.WriteString(strings.Repeat(" ", 4+2*(wk-from)-labels.Len()) + label)
+		}
+	}
+	b.WriteString(labels.String() + "\n")
+
+	for d := range 7 {
+		label := ""
+		if d%2 == 0 {
+			label = first.AddDate(0, 0, d).Format("Mon")
+		}
+		b.WriteString(fmt.Sprintf("%-4s", label))
+		for wk := from; wk < from+weeks; wk++ {
+			day := first.AddDate(0, 0, 7*wk+d)
+			cell := "■"
+			if day.Year() != year || day.After(m.today) {
+				b.WriteString("  ")
+				continue
+			}
+			style := m.dayStyle(day)
+			if day.Equal(m.sel) {
+				style = style.Inherit(selectedStyle)
+			}
+			b.WriteString(style.Render(cell) + " ")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + m.sel.Format("Monday, January 2, 2006") + "  " + m.dayStatus(m.sel) + "\n\n")
+	if m.status != "" {
+		b.WriteString(m.status + "\n\n")
+	}
+	b.WriteString(m.legend())
+	return b.String()
+}
+
+// monthView shows the month of the selected day.
+func (m calendarModel) monthView() string {
+	first := time.Date(m.sel.Year(), m.sel.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	// How many of the month's puzzles (so far) were played?
+	days := first.AddDate(0, 1, -1).Day()
+	if first.Year() == m.today.Year() && first.Month() == m.today.Month() {
+		days = m.today.Day()
+	}
+	header := first.Format("January 2006") + " — …"
+	if c, ok := m.summary(first.Format("2006-01")); ok {
+		header = tr("%s — %d/%d played, %d completed", first.Format("January 2006"), c.played, days, c.solved)
+	}
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("[ Bracket City | "+header+" ]") + "\n\n")
+	b.WriteString(" Mo  Tu  We  Th  Fr  Sa  Su\n")
+	b.WriteString(strings.Repeat("    ", (int(first.Weekday())+6)%7))
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%3d", day.Day())
+		style := m.dayStyle(day)
+		if day.After(m.today) {
+			style = unplayedStyle
+		}
+		if day.Equal(m.sel) {
+			style = style.Inherit(selectedStyle)
+		}
+		b.WriteString(style.Render(cell) + " ")
+		if day.Weekday() == time.Sunday {
+			b.WriteString("\n")
+		}
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + m.sel.Format("Monday, January 2, 2006") + "  " + m.dayStatus(m.sel) + "\n\n")
+	if m.status != "" {
+		b.WriteString(m.status + "\n\n")
+	}
+	b.WriteString(m.legend())
+	return b.String()
+}
+
+// legend explains the colors and lists the keys, unless it's been
+// collapsed.
+func (m calendarModel) legend() string {
+	show := keys.Legend
+	if m.hideLegend {
+		show.SetHelp(show.Help().Key, "show legend")
+		return noticeStyle.Render(helpLine(show))
+	}
+
+	type swatch struct {
+		style lipgloss.Style
+		desc  string
+	}
+	swatches := []swatch{
+		{solvedStyles[0], "solved"},
+		{solvedStyles[1], "1–3 incorrect"},
+		{solvedStyles[2], "4+ incorrect"},
+		{inProgressStyle, "in progress"},
+		{gaveUpStyle, "gave up"},
+		{unplayedStyle, "not played"},
+	}
+	if len(m.failed) > 0 {
+		swatches = append(swatches, swatch{failedStyle, "couldn't load"})
+	}
+	var parts []string
+	for _, sw := range swatches {
+		parts = append(parts, sw.style.Render("■")+" "+tr(sw.desc))
+	}
+
+	enter := keys.Submit
+	enter.SetHelp("enter", "zoom")
+	if m.month {
+		enter.SetHelp("enter", "play")
+	}
+	leave := keys.Quit
+	if m.pane {
+		leave = keys.Focus
+	}
+	return strings.Join(parts, "  ") + "\n" +
+		noticeStyle.Render(helpLine(keys.Up, keys.Down, keys.Left, keys.Right, enter, keys.Zoom, todayHelp(m.store, false), keys.Legend, leave))
+}
+
+// dayStatus describes the game on a day.
+func (m calendarModel) dayStatus(day time.Time) string {
+	if err := m.failed[day.Format(time.DateOnly)]; err != nil {
+		return failedStyle.Render(tr("couldn't load the puzzle: %v", err)) + "  " +
+			noticeStyle.Render(helpLine(keys.Retry))
+	}
+	gs, ok := m.store.game(day.Format(time.DateOnly))
+	if !ok {
+		return m.dayStyle(day).Render(tr("not played"))
+	}
+	status := tr("in progress")
+	switch {
+	case gs.GaveUp:
+		status = tr("gave up")
+	case gs.Done:
+		status = tr("solved")
+	}
+	status += fmt.Sprintf(" · ✅ %d ❌ %d", gs.Correct, gs.Incorrect)
+	if gs.ElapsedSeconds > 0 {
+		status += " ⏱️ " + formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second)
+	}
+	return m.dayStyle(day).Render(status)
+}
+
+// runCalendar shows the calendar, then plays the chosen day's puzzle.
+func runCalendar() error {
+	cfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	setLocale(cfg.Locale)
+	path, err := storePath(cfg)
+	if err != nil {
+		return err
+	}
+	s, err := openStore(path)
+	if err != nil {
+		return err
+	}
+	return showCalendar(s, cfg, time.Now())
+}
+
+// showCalendar shows the calendar with a day selected. Choosing a day
+// opens the game over it.
+func showCalendar(s *store, cfg config, sel time.Time) error {
+	cm := newCalendarModel(s, time.Now())
+	cm.cfg = cfg
+	cm = cm.move(int(sel.Sub(cm.sel).Round(24*time.Hour).Hours() / 24))
+
+	// Detect the background color before the calendar starts reading
+	// input
+	setTheme(cfg.Theme)
+
+	top, err := runViews(cm, whatsNew(s)...)
+	if err != nil {
+		return err
+	}
+	if cfg.RestoreSession {
+		return saveLastView(s, top)
+	}
+	return nil
+}
+
+// play loads the selected day's puzzle, and opens the game over the
+// calendar.
+func (m calendarModel) play() tea.Cmd {
+	s, cfg, d := m.store, m.cfg, m.sel
+	return func() tea.Msg {
+		p, err := loadPuzzle(s, d)
+		if err != nil {
+			return puzzleLoadedMsg{date: d, err: err}
+		}
+		return pushMsg{view: newSession(newModel(p, s, cfg))}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// metaChangelogSeen is the metadata key for the newest version whose
+// changes have been shown.
+const metaChangelogSeen = "changelog.seen"
+
+// changes is a version's entry in the changelog.
+type changes struct {
+	version  string
+	features []string
+	keys     []string // new or changed key bindings
+}
+
+// changelog lists what's new in each version, newest first. Add an
+// entry when bumping the version.
+var changelog = []changes{
+	{
+		version: "0.0.3",
+		features: []string{
+			"A home screen once today's puzzle is done, with a countdown to the next one",
+			"A calendar with a year heatmap, shown beside the game on wide terminals",
+			"Open puzzles in tabs, and pick up where the last session left off",
+			"Hard mode, expert mode, select mode and a ghost of your fastest attempt",
+			"brack warmup and brack review, to practice previously solved clues",
+			"Notes and attempt history on the results screen",
+		},
+		keys: []string{
+			"ctrl+p: go to...",
+			": command palette",
+			"1-9: switch tab",
+			"alt+t: today's puzzle",
+			"shift+tab: switch pane",
+			"e: edit note (on the results screen)",
+		},
+	},
+}
+
+// unseenChanges returns the changelog entries newer than the last ones
+// shown, marking them seen. New installs have nothing to catch up on,
+// and upgrades from before the changelog only get the latest entry.
+func unseenChanges(s *store) []changes {
+	if s == nil {
+		return nil
+	}
+	seen := s.meta(metaChangelogSeen)
+	if compareVersions(seen, version) >= 0 {
+		return nil
+	}
+	if err := s.setMeta(metaChangelogSeen, version); err != nil {
+		slog.Error("failed to save the changelog version", "error", err)
+	}
+	if seen == "" {
+		if len(s.data.Games) == 0 {
+			return nil
+		}
+		return changelog[:1]
+	}
+
+	var rs []changes
+	for _, r := range changelog {
+		if compareVersions(r.version, seen) > 0 {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// whatsNew returns the changelog view for an upgrade, to open over the
+// first view, or nothing if it's been seen.
+func whatsNew(s *store) []tea.Model {
+	rs := unseenChanges(s)
+	if len(rs) == 0 {
+		return nil
+	}
+	return []tea.Model{changelogModel{releases: rs}}
+}
+
+var _ tea.Model = changelogModel{}
+
+// changelogModel shows what's new in some versions.
+type changelogModel struct {
+	releases []changes
+	w        int
+}
+
+func (m changelogModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m changelogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w = msg.Width
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Close, keys.Submit, keys.WhatsNew):
+			return m, popView
+		}
+	}
+	return m, nil
+}
+
+func (m changelogModel) View() string {
+	var b strings.Builder
+	para := lipgloss.NewStyle().Width(min(m.w, 80) - 2)
+
+	for _, r := range m.releases {
+		b.WriteString(headerStyle.Render(tr("What's new in brack v%s", r.version)) + "\n")
+		for _, f := range r.features {
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "• ", para.Render(tr(f))) + "\n")
+		}
+		if len(r.keys) > 0 {
+			b.WriteString(tr("New and changed keys:") + "\n")
+			for _, k := range r.keys {
+				b.WriteString("  " + k + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(noticeStyle.Render("esc: " + tr("back")))
+
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseAge parses an age like "180d", "4w" or "36h" (anything
+// time.ParseDuration accepts, plus days and weeks).
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// runClean deletes cached puzzles (and, unless puzzlesOnly is set,
+// saved games and archived attempts) for dates older than the given
+// age.
+func runClean(w io.Writer, s *store, age time.Duration, puzzlesOnly, yes bool) error {
+	cutoff := time.Now().Add(-age).Format(time.DateOnly)
+	puzzles, games, attempts := s.datesBefore(cutoff)
+	if puzzlesOnly {
+		games, attempts = nil, nil
+	}
+	archived := 0
+	for _, d := range attempts {
+		archived += len(s.attempts(d))
+	}
+	if len(puzzles) == 0 && len(games) == 0 && len(attempts) == 0 {
+		fmt.Fprintf(w, "Nothing to clean from before %s\n", cutoff)
+		return nil
+	}
+	if err := lockDates(s, append(games, attempts...)); err != nil {
+		return err
+	}
+
+	what := fmt.Sprintf("%d cached puzzles", len(puzzles))
+	if !puzzlesOnly {
+		what += fmt.Sprintf(", %d saved games and %d archived attempts", len(games), archived)
+	}
+	if ok, err := confirmOrYes("Delete "+what+" from before "+cutoff+"?", yes); err != nil {
+		return err
+	} else if !ok {
+		fmt.Fprintln(w, "Cancelled")
+		return nil
+	}
+
+	if err := s.deleteBefore(cutoff, puzzlesOnly); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Deleted %s\n", what)
+	return nil
+}
+
+// runReset archives the saved game for a date, so the puzzle can be
+// played again from scratch.
+func runReset(w io.Writer, s *store, d time.Time, yes bool) error {
+	date := d.Format(time.DateOnly)
+	if _, ok := s.game(date); !ok {
+		fmt.Fprintf(w, "No saved game for %s\n", date)
+		return nil
+	}
+	if err := lockDates(s, []string{date}); err != nil {
+		return err
+	}
+	if ok, err := confirmOrYes("Reset your progress on the puzzle for "+date+"?", yes); err != nil {
+		return err
+	} else if !ok {
+		fmt.Fprintln(w, "Cancelled")
+		return nil
+	}
+
+	if err := s.archiveGame(date); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Reset the puzzle for %s (your previous attempt was archived)\n", date)
+	return nil
+}
+
+// lockDates takes the locks on the dates whose games are about to be
+// changed, so a brack playing one can't save over the change, and
+// gives them up when the process exits. It fails if another brack has
+// one open.
+func lockDates(s *store, dates []string) error {
+	for _, date := range dates {
+		holder, ok, err := s.lock(date)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("the puzzle for %s is %w (%s), close it there first", date, errPuzzleLocked, holder)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Without a terminal to confirm on (as under go test), clean and reset
+// fail unless --yes is passed, rather than quietly doing nothing.
+func TestCleanWithoutTerminal(t *testing.T) {
+	s, err := openStore(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.saveGame(gamestate{Date: "2024-01-01", Correct: 1}); err != nil {
+		t.Fatal(err)
+	}
+	d := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	if err := runReset(io.Discard, s, d, false); err == nil {
+		t.Error("reset without --yes didn't fail")
+	}
+	if err := runClean(io.Discard, s, 24*time.Hour, false, false); err == nil {
+		t.Error("clean without --yes didn't fail")
+	}
+	if _, ok := s.game("2024-01-01"); !ok {
+		t.Fatal("the game was deleted without confirming")
+	}
+
+	if err := runReset(io.Discard, s, d, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.game("2024-01-01"); ok {
+		t.Error("reset --yes kept the game")
+	}
+	if n := len(s.attempts("2024-01-01")); n != 1 {
+		t.Errorf("reset --yes archived %d attempts, want 1", n)
+	}
+}
+
+// Archived attempts are cleaned (and counted) like saved games, even
+// when there's nothing else to clean.
+func TestCleanAttempts(t *testing.T) {
+	s, err := openStore(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.data.Attempts["2024-01-01"] = []gamestate{{Date: "2024-01-01"}, {Date: "2024-01-01"}}
+	s.data.Attempts["2024-01-02"] = []gamestate{{Date: "2024-01-02"}}
+
+	var out strings.Builder
+	if err := runClean(&out, s, 24*time.Hour, true, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.attempts("2024-01-01")) != 2 {
+		t.Error("--puzzles-only deleted archived attempts")
+	}
+
+	out.Reset()
+	if err := runClean(&out, s, 24*time.Hour, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Deleted 0 cached puzzles, 0 saved games and 3 archived attempts"; !strings.Contains(out.String(), want) {
+		t.Errorf("clean said %q, want %q", out.String(), want)
+	}
+	if n := len(s.data.Attempts); n != 0 {
+		t.Errorf("%d dates of archived attempts are left", n)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"180d": 180 * 24 * time.Hour,
+		"4w":   28 * 24 * time.Hour,
+		"36h":  36 * time.Hour,
+	} {
+		if got, err := parseAge(in); err != nil || got != want {
+			t.Errorf("parseAge(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "-1d", "xd", "soon"} {
+		if _, err := parseAge(in); err == nil {
+			t.Errorf("parseAge(%q) didn't fail", in)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// copiedMsg reports the result of copying something to the clipboard.
+type copiedMsg struct {
+	what string
+	err  error
+}
+
+// copyToClipboard copies s to the system clipboard. Over SSH (or when
+// there's no clipboard tool to use) it asks the terminal to do it with
+// an OSC 52 escape sequence instead, which most terminals support.
+func copyToClipboard(what, s string) tea.Cmd {
+	return func() tea.Msg {
+		if os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" {
+			termenv.Copy(s)
+			return copiedMsg{what: what}
+		}
+		if err := clipboard.WriteAll(s); err != nil {
+			slog.Debug("no system clipboard, using OSC 52", "err", err)
+			termenv.Copy(s)
+		}
+		return copiedMsg{what: what}
+	}
+}
+
+// shareText summarizes a finished game, for sharing.
+func (m model) shareText() string {
+	return shareText(m.gamestate(), m.data.CompletionURL)
+}
+
+// shareText summarizes a game without spoiling the puzzle, with the
+// puzzle's completion URL if it's known.
+func shareText(gs gamestate, url string) string {
+	assisted := ""
+	if gs.Assisted {
+		assisted = " 🛟"
+	}
+	s := fmt.Sprintf(
+		"Bracket City %s\n✅ %d ❌ %d ⌨️ %d ⏱️ %s%s",
+		gs.Date,
+		gs.Correct,
+		gs.Incorrect,
+		gs.Chars,
+		formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second),
+		assisted,
+	)
+	if url != "" {
+		s += "\n" + url
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// The palette, with a fallback for each color on 256 and 16 color
+// terminals. Left to lipgloss, the greens (and the two dark greys) are
+// rounded to the same basic color, so on a 16 color terminal the
+// calendar's shades of green are picked to stay apart.
+var (
+	colorYellow = lipgloss.CompleteColor{TrueColor: "#e8c566", ANSI256: "221", ANSI: "11"}
+	colorRed    = lipgloss.CompleteColor{TrueColor: "#e06c75", ANSI256: "204", ANSI: "9"}
+	colorGrey   = lipgloss.CompleteColor{TrueColor: "#5c6370", ANSI256: "241", ANSI: "8"}
+	colorInk    = lipgloss.CompleteColor{TrueColor: "#0f0f0f", ANSI256: "233", ANSI: "0"}
+
+	// Shades of green, brightest first.
+	colorGreens = []lipgloss.CompleteColor{
+		{TrueColor: "#56d364", ANSI256: "77", ANSI: "10"},
+		{TrueColor: "#2ea043", ANSI256: "34", ANSI: "2"},
+		{TrueColor: "#196c2e", ANSI256: "22", ANSI: "6"},
+	}
+)
+
+// colorProfiles are the values of the color config setting, other
+// than "auto".
+var colorProfiles = map[string]termenv.Profile{
+	"truecolor": termenv.TrueColor,
+	"256":       termenv.ANSI256,
+	"16":        termenv.ANSI,
+	"none":      termenv.Ascii,
+}
+
+// setColorProfile overrides how many colors the terminal is taken to
+// support, which is otherwise detected from the environment (e.g.
+// COLORTERM, TERM and NO_COLOR).
+func setColorProfile(name string) error {
+	if err := checkColor(name); err != nil {
+		return err
+	}
+	if p, ok := colorProfiles[name]; ok {
+		lipgloss.SetColorProfile(p)
+	}
+	return nil
+}
+
+// checkColor returns an error for an unknown color setting.
+func checkColor(name string) error {
+	if _, ok := colorProfiles[name]; !ok && name != "" && name != "auto" {
+		return fmt.Errorf("unknown color setting %q (expected auto, truecolor, 256, 16 or none)", name)
+	}
+	return nil
+}
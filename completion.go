@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// darkBackground is whether the terminal has a dark background. It's
+// detected before the game starts, since querying the terminal while
+// the game is reading input could swallow keypresses.
+var darkBackground = true
+
+// setTheme sets darkBackground from the theme setting, detecting it
+// from the terminal for "auto" (or no setting).
+func setTheme(theme string) {
+	switch theme {
+	case "dark":
+		darkBackground = true
+	case "light":
+		darkBackground = false
+	default:
+		darkBackground = lipgloss.HasDarkBackground()
+	}
+}
+
+// checkTheme returns an error for an unknown theme.
+func checkTheme(theme string) error {
+	switch theme {
+	case "", "auto", "dark", "light":
+		return nil
+	}
+	return fmt.Errorf("unknown theme %q (expected auto, dark or light)", theme)
+}
+
+// glamourStyle picks the markdown style to match the terminal.
+func glamourStyle() string {
+	switch {
+	case lipgloss.ColorProfile() == termenv.Ascii:
+		return "notty"
+	case darkBackground:
+		return "dark"
+	default:
+		return "light"
+	}
+}
+
+// renderCompletionText renders a puzzle's completion text, which can
+// contain some formatting, as markdown wrapped to width. If it can't
+// be rendered it's returned as-is.
+func renderCompletionText(text string, width int) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	if width <= 0 {
+		width = maxBodyWidth
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(glamourStyle()),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		slog.Error("failed to create markdown renderer", "err", err)
+		return text
+	}
+	out, err := r.Render(text)
+	if err != nil {
+		slog.Error("failed to render completion text", "err", err)
+		return text
+	}
+	return strings.Trim(out, "\n")
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// config is the user's configuration, read from a TOML file.
+type config struct {
+	// CheckForUpdates enables the (daily) background check for a
+	// newer release of brack.
+	CheckForUpdates bool `toml:"check_for_updates"`
+
+	// DBPath overrides the location of the database.
+	DBPath string `toml:"db_path"`
+
+	// Profile plays as a named profile, with its own database kept
+	// beside the main one (see storePath).
+	Profile string `toml:"profile"`
+
+	// Theme is the color theme: "auto" (the default), detected from
+	// the terminal's background, "dark" or "light".
+	Theme string `toml:"theme"`
+
+	// Endpoint overrides the URL of the puzzle API.
+	Endpoint string `toml:"endpoint"`
+
+	// StreamerMode starts the game in streamer mode, hiding
+	// spoilers from an audience (see model.streamer).
+	StreamerMode bool `toml:"streamer_mode"`
+
+	// IgnoreAccents accepts answers regardless of diacritics (e.g.
+	// "cafe" for "café").
+	IgnoreAccents bool `toml:"ignore_accents"`
+
+	// Locale sets the UI language (e.g. "en" or "es"), overriding
+	// the one from the environment.
+	Locale string `toml:"locale"`
+
+	// NudgeAfter is how many minutes without a correct answer before
+	// offering a nudge towards a clue. Zero turns nudges off.
+	NudgeAfter int `toml:"nudge_after"`
+
+	// DictionaryCommand is a command to look up words with (e.g.
+	// "dict -d wn"), given the word as its last argument. Otherwise
+	// DictionaryURL is used, a web API URL with %s for the word.
+	DictionaryCommand string `toml:"dictionary_command"`
+	DictionaryURL     string `toml:"dictionary_url"`
+
+	// WebhookURL is posted a JSON summary of each solved puzzle.
+	WebhookURL string `toml:"webhook_url"`
+
+	// BadgePath is where to write an SVG badge of the current streak
+	// each time a puzzle is solved (see streakBadge).
+	BadgePath string `toml:"badge_path"`
+
+	// DiscordToken is the token of the Discord bot that brack bot
+	// discord runs as.
+	DiscordToken string `toml:"discord_token"`
+
+	// SlackSigningSecret is the signing secret of the Slack app whose
+	// slash command brack bot slack answers.
+	SlackSigningSecret string `toml:"slack_signing_secret"`
+
+	// EncryptDB encrypts the database at rest, with a passphrase from
+	// $BRACK_PASSPHRASE or asked for on starting, or with the key in
+	// DBKeyFile, which turns encryption on by itself (see encryptStore).
+	EncryptDB bool   `toml:"encrypt_db"`
+	DBKeyFile string `toml:"db_key_file"`
+
+	// RestoreSession opens the view and puzzle that were open on
+	// quitting, when brack is run without a date.
+	RestoreSession bool `toml:"restore_session"`
+
+	// AnswerMode is how answers are entered: "type" (the default),
+	// checked against every clue, or "select", choosing a clue by its
+	// number first (see model.selectMode).
+	AnswerMode string `toml:"answer_mode"`
+
+	// PartialCredit shows which words of a multi-word answer a wrong
+	// guess got right. It's a hint, so it marks the game as assisted.
+	PartialCredit bool `toml:"partial_credit"`
+
+	// SpoilerLock asks before showing the answers to a puzzle that
+	// hasn't been solved, e.g. after giving up on it, or in brack
+	// trivia (see spoilerLocked).
+	SpoilerLock bool `toml:"spoiler_lock"`
+
+	// ExpertMode starts new games in expert mode, where clues must be
+	// solved innermost first (see innermostQuestions).
+	ExpertMode bool `toml:"expert_mode"`
+
+	// HardMode makes you wait GuessCooldown seconds after a wrong
+	// guess before guessing again, so answers can't be brute-forced.
+	HardMode      bool `toml:"hard_mode"`
+	GuessCooldown int  `toml:"guess_cooldown"`
+
+	// Color overrides the detected color support: "auto" (the
+	// default), "truecolor", "256", "16" or "none".
+	Color string `toml:"color"`
+
+	// StatusLine replaces the score line with a template, e.g.
+	// "{correct}/{total} | errors {incorrect} | {elapsed}" (see
+	// statusFields).
+	StatusLine string `toml:"status_line"`
+
+	// Glyphs picks the symbols the UI is drawn with: "auto" (the
+	// default), "emoji", "text" or "ascii" (see setGlyphs). NoEmoji
+	// is a shorthand for "text", swapping emoji for labels like OK
+	// and ERR.
+	Glyphs  string `toml:"glyphs"`
+	NoEmoji bool   `toml:"no_emoji"`
+
+	// MaxWidth is the widest the game is laid out, in columns (100
+	// by default). In a wider window it's centered, unless NoCenter
+	// is set.
+	MaxWidth int  `toml:"max_width"`
+	NoCenter bool `toml:"no_center"`
+
+	// CABundle is a PEM file of extra certificates to trust, e.g. for
+	// a corporate proxy that intercepts TLS.
+	CABundle string `toml:"ca_bundle"`
+
+	// InputKeys rebinds the answer input's editing actions, e.g.
+	// delete_word_backward = ["ctrl+w"] (see inputActions).
+	InputKeys map[string][]string `toml:"input_keys"`
+}
+
+func defaultConfig() config {
+	return config{
+		CheckForUpdates: true,
+		NudgeAfter:      5,
+		MaxWidth:        maxBodyWidth,
+		GuessCooldown:   defaultGuessCooldown,
+	}
+}
+
+// configPath returns the path of the config file, following the XDG
+// base directory spec where it applies.
+func configPath() (string, error) {
+	if d := os.Getenv("XDG_CONFIG_HOME"); d != "" {
+		return filepath.Join(d, "brack", "config.toml"), nil
+	}
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, "brack", "config.toml"), nil
+}
+
+// expandHome expands a leading ~ in a path from the config to the home
+// directory, with either kind of slash after it on Windows.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~`+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// loadConfig reads the config file at path over the defaults. A
+// missing file isn't an error.
+func loadConfig(path string) (config, error) {
+	cfg := defaultConfig()
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := checkInputKeys(cfg.InputKeys); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if _, err := parseStatusLine(cfg.StatusLine); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: status_line: %w", path, err)
+	}
+	if err := checkColor(cfg.Color); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: color: %w", path, err)
+	}
+	if err := checkGlyphs(cfg.Glyphs); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: glyphs: %w", path, err)
+	}
+	if err := checkAnswerMode(cfg.AnswerMode); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: answer_mode: %w", path, err)
+	}
+	if err := checkTheme(cfg.Theme); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: theme: %w", path, err)
+	}
+	if err := checkProfile(cfg.Profile); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: profile: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// loadUserConfig loads the config from the default location, with any
+// overrides from the environment and flags (see resolveConfig).
+func loadUserConfig() (config, error) {
+	path, err := configPath()
+	if err != nil {
+		return config{}, err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return cfg, err
+	}
+	return resolveConfig(cfg)
+}
+
+// tableHeader matches the header of a table (or array of tables) in a
+// TOML file.
+var tableHeader = regexp.MustCompile(`(?m)^[ \t]*\[`)
+
+// setConfigString sets a top-level string key in the config file at
+// path, creating the file if needed. The rest of the file (including
+// comments) is left as-is.
+func setConfigString(path, key, value string) error {
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Replace the existing value, or add it to the top of the
+	// file so it isn't inside a table. Only the top-level keys, before
+	// the first table header, are looked at, so the same key in a
+	// table is left alone.
+	line := key + " = " + strconv.Quote(value)
+	top, tables := b, []byte(nil)
+	if loc := tableHeader.FindIndex(b); loc != nil {
+		top, tables = b[:loc[0]], b[loc[0]:]
+	}
+	re := regexp.MustCompile(`(?m)^[ \t]*` + regexp.QuoteMeta(key) + `[ \t]*=.*$`)
+	if re.Match(top) {
+		b = append(re.ReplaceAllLiteral(top, []byte(line)), tables...)
+	} else {
+		b = append([]byte(line+"\n"), b...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetConfigString(t *testing.T) {
+	for _, tt := range []struct {
+		name, in, want string
+	}{
+		{"missing file", "", "db_path = \"/new\"\n"},
+		{"new key", "theme = \"dark\"\n", "db_path = \"/new\"\ntheme = \"dark\"\n"},
+		{"existing key", "# comment\ndb_path = \"/old\"\ntheme = \"dark\"\n", "# comment\ndb_path = \"/new\"\ntheme = \"dark\"\n"},
+		{"indented", "  db_path= \"/old\"\n", "db_path = \"/new\"\n"},
+		{
+			"same key in a table",
+			"theme = \"dark\"\n\n[backup]\ndb_path = \"/other\"\n",
+			"db_path = \"/new\"\ntheme = \"dark\"\n\n[backup]\ndb_path = \"/other\"\n",
+		},
+		{
+			"blank lines before the key",
+			"\n\ndb_path = \"/old\"\n",
+			"\n\ndb_path = \"/new\"\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if tt.in != "" {
+				if err := os.WriteFile(path, []byte(tt.in), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := setConfigString(path, "db_path", "/new"); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveStoreEmptyPath(t *testing.T) {
+	if err := moveStore(nil, defaultConfig(), ""); err == nil {
+		t.Error("moving the database to an empty path didn't fail")
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// configProblem is something wrong in the config file, at a line (or
+// zero when it isn't known).
+type configProblem struct {
+	line int
+	msg  string
+}
+
+// typingKeys are the game's bindings that take a key before the answer
+// input sees it, so rebinding an input action to one of them does
+// nothing.
+var typingKeys = []key.Binding{
+	keys.Submit, keys.Dismiss, keys.Streamer, keys.Info, keys.Pause, keys.Retry,
+	keys.GiveUp, keys.Helper, keys.Launcher, keys.Focus, keys.Today, keys.Quit,
+}
+
+// checkConfig checks the config file's source, returning every
+// problem found rather than just the first (as loadConfig does).
+func checkConfig(src string) []configProblem {
+	cfg := defaultConfig()
+	md, err := toml.Decode(src, &cfg)
+	if err != nil {
+		// The error includes the line
+		return []configProblem{{msg: strings.TrimPrefix(err.Error(), "toml: ")}}
+	}
+
+	var ps []configProblem
+	add := func(k toml.Key, err error) {
+		if err != nil {
+			ps = append(ps, configProblem{line: keyLine(src, k), msg: k.String() + ": " + err.Error()})
+		}
+	}
+	for _, k := range md.Undecoded() {
+		// Unknown input actions are reported below
+		if len(k) == 2 && k[0] == "input_keys" {
+			continue
+		}
+		ps = append(ps, configProblem{line: keyLine(src, k), msg: fmt.Sprintf("unknown key %q", k.String())})
+	}
+
+	add(toml.Key{"color"}, checkColor(cfg.Color))
+	add(toml.Key{"glyphs"}, checkGlyphs(cfg.Glyphs))
+	add(toml.Key{"answer_mode"}, checkAnswerMode(cfg.AnswerMode))
+	add(toml.Key{"locale"}, checkLocale(cfg.Locale))
+	add(toml.Key{"theme"}, checkTheme(cfg.Theme))
+	add(toml.Key{"profile"}, checkProfile(cfg.Profile))
+	_, err = parseStatusLine(cfg.StatusLine)
+	add(toml.Key{"status_line"}, err)
+
+	// Input keys, which can be unknown or taken by another action
+	names := make([]string, 0, len(cfg.InputKeys))
+	for name := range cfg.InputKeys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	in := newInput(cfg)
+	for _, name := range names {
+		k := toml.Key{"input_keys", name}
+		if err := checkInputKeys(map[string][]string{name: cfg.InputKeys[name]}); err != nil {
+			add(k, err)
+			continue
+		}
+		for _, s := range cfg.InputKeys[name] {
+			for _, other := range sortedInputActions() {
+				if other != name && slices.Contains(inputActions[other](&in.KeyMap).Keys(), s) {
+					add(k, fmt.Errorf("%s is also bound to %s", s, other))
+				}
+			}
+			for _, b := range typingKeys {
+				if slices.Contains(b.Keys(), s) {
+					add(k, fmt.Errorf("%s is already the game's %q key", s, b.Help().Desc))
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(ps, func(a, b configProblem) int {
+		return a.line - b.line
+	})
+	return ps
+}
+
+// keyLine returns the line a key is set on in a TOML file's source, or
+// the line of its closest parent (e.g. an inline table), or zero if it
+// can't be found.
+func keyLine(src string, k toml.Key) int {
+	for n := len(k); n > 0; n-- {
+		want := k[:n].String()
+		var table string
+		for i, line := range strings.Split(src, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "[") {
+				table = strings.Trim(line, "[] ")
+				if table == want {
+					return i + 1
+				}
+				continue
+			}
+			name, _, ok := strings.Cut(line, "=")
+			if !ok || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name = strings.Trim(strings.TrimSpace(name), `"'`)
+			if table != "" {
+				name = table + "." + name
+			}
+			if name == want {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// runConfigCheck checks the config file, writing its problems (with
+// their line numbers) to w. Finding any is an error.
+func runConfigCheck(w io.Writer, path string) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(w, "%s doesn't exist, so the defaults are used\n", path)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ps := checkConfig(string(b))
+	for _, p := range ps {
+		if p.line > 0 {
+			fmt.Fprintf(w, "%s:%d: %s\n", path, p.line, p.msg)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", path, p.msg)
+		}
+	}
+	if len(ps) > 0 {
+		return fmt.Errorf("found %d problem(s) in the config file", len(ps))
+	}
+	fmt.Fprintf(w, "✓ %s is valid\n", path)
+	return nil
+}
+
+// runConfigEdit opens the config file in $VISUAL or $EDITOR, creating
+// it if needed, then checks it.
+func runConfigEdit(w io.Writer, path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	// The editor may have arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", editor, err)
+	}
+	return runConfigCheck(w, path)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultGuessCooldown is how long hard mode makes you wait after a
+// wrong guess, unless the config says otherwise.
+const defaultGuessCooldown = 5
+
+// cooldown returns how long hard mode makes you wait after a wrong
+// guess, or zero when it's off.
+func (m model) cooldown() time.Duration {
+	if !m.cfg.HardMode {
+		return 0
+	}
+	secs := m.cfg.GuessCooldown
+	if secs <= 0 {
+		secs = defaultGuessCooldown
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// cooldownDoneMsg redraws the game when the cooldown ends, rather than
+// at the timer's next tick.
+type cooldownDoneMsg struct{}
+
+// startCooldown starts the cooldown after a wrong guess, if hard mode
+// is on.
+func (m *model) startCooldown() tea.Cmd {
+	d := m.cooldown()
+	if d == 0 {
+		return nil
+	}
+	m.cooldownUntil = time.Now().Add(d)
+	return tea.Tick(d, func(time.Time) tea.Msg { return cooldownDoneMsg{} })
+}
+
+// cooldownLeft returns how long until another guess can be made.
+func (m model) cooldownLeft() time.Duration {
+	return max(time.Until(m.cooldownUntil), 0)
+}
+
+// cooldownView counts down to when another guess can be made. The
+// timer's tick redraws it each second.
+func (m model) cooldownView() string {
+	secs := int(math.Ceil(m.cooldownLeft().Seconds()))
+	return errorStyle.Render(tr("⏳ Hard mode: wait %ds to guess again", secs))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// crashReport is the path of the crash report written after a panic
+// in the game, if there was one.
+var crashReport string
+
+// recoverPanic saves the game state and writes a crash report if the
+// model panicked with r, before re-panicking so that Bubble Tea can
+// restore the terminal. It's deferred in a closure, passed recover(),
+// so that m is the model as it was when it panicked:
+//
+//	defer func() { m.recoverPanic(recover()) }()
+func (m *model) recoverPanic(r any) {
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+	slog.Error("panic", "err", r, "stack", string(stack))
+
+	// Save the progress made so far
+	m.save()
+
+	// Write the crash report
+	path, err := writeCrashReport(r, stack, m.gamestate())
+	if err != nil {
+		slog.Error("failed to write crash report", "err", err)
+	} else {
+		crashReport = path
+	}
+	panic(r)
+}
+
+func writeCrashReport(r any, stack []byte, gs gamestate) (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	state, _ := json.MarshalIndent(gs, "", "  ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "brack %s crashed at %s\n", version, now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n\n%s\n", r, stack)
+	fmt.Fprintf(&b, "game state:\n%s\n", state)
+
+	path := filepath.Join(dir, "crash-"+now.Format("20060102-150405")+".txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
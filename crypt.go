@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// encryptedMagic starts an encrypted database file. After it come the
+// salt the key was derived with, the nonce, and the sealed JSON.
+const encryptedMagic = "brack-encrypted-v1\n"
+
+// keyIterations is how many PBKDF2 rounds a key takes to derive, to
+// slow down guessing a passphrase from a copy of the database.
+const keyIterations = 600_000
+
+const saltSize = 16
+
+// passphraseEnv is the environment variable a passphrase is read from,
+// rather than asked for on the terminal.
+const passphraseEnv = "BRACK_PASSPHRASE"
+
+// dbCrypt is how databases are encrypted at rest, set from the config
+// at startup (see setupEncryption). Keys are derived once per salt, as
+// deriving one is slow on purpose.
+var dbCrypt struct {
+	mu      sync.Mutex
+	encrypt bool   // whether databases are written encrypted
+	keyFile string // the key file, instead of a passphrase
+	secret  []byte // the key file's contents, or the passphrase
+	salt    []byte // the salt new files are encrypted with
+	keys    map[string][]byte
+}
+
+// setupEncryption sets whether databases are written encrypted, and
+// with what: the contents of keyFile, or if it's empty a passphrase.
+// Encrypted databases can be read either way, so turning encryption
+// off decrypts the database the next time it's written.
+func setupEncryption(encrypt bool, keyFile string) {
+	dbCrypt.mu.Lock()
+	defer dbCrypt.mu.Unlock()
+	dbCrypt.encrypt = encrypt || keyFile != ""
+	dbCrypt.keyFile = keyFile
+	dbCrypt.secret, dbCrypt.salt, dbCrypt.keys = nil, nil, nil
+}
+
+// isEncrypted reports whether a database file's contents are encrypted.
+func isEncrypted(b []byte) bool {
+	return bytes.HasPrefix(b, []byte(encryptedMagic))
+}
+
+// encryptedNote notes that the database file at path is encrypted, for
+// brack doctor.
+func encryptedNote(path string) string {
+	if b, err := os.ReadFile(path); err == nil && isEncrypted(b) {
+		return " (encrypted)"
+	}
+	return ""
+}
+
+// readSecret reads the key file, or the passphrase from $BRACK_PASSPHRASE
+// or the terminal. It's called with dbCrypt.mu held.
+func readSecret() ([]byte, error) {
+	if dbCrypt.secret != nil {
+		return dbCrypt.secret, nil
+	}
+	switch {
+	case dbCrypt.keyFile != "":
+		b, err := os.ReadFile(expandHome(dbCrypt.keyFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading the database key file: %w", err)
+		}
+		dbCrypt.secret = bytes.TrimSpace(b)
+	case os.Getenv(passphraseEnv) != "":
+		dbCrypt.secret = []byte(os.Getenv(passphraseEnv))
+	case term.IsTerminal(os.Stdin.Fd()):
+		fmt.Fprint(os.Stderr, "Database passphrase: ")
+		b, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		dbCrypt.secret = []byte(strings.TrimSpace(string(b)))
+	default:
+		return nil, fmt.Errorf("the database is encrypted, set %s to its passphrase", passphraseEnv)
+	}
+	if len(dbCrypt.secret) == 0 {
+		dbCrypt.secret = nil
+		return nil, errors.New("the database passphrase (or key file) is empty")
+	}
+	return dbCrypt.secret, nil
+}
+
+// storeKey returns the key for a salt, deriving it the first time.
+// It's called with dbCrypt.mu held.
+func storeKey(salt []byte) ([]byte, error) {
+	if key, ok := dbCrypt.keys[string(salt)]; ok {
+		return key, nil
+	}
+	secret, err := readSecret()
+	if err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, string(secret), salt, keyIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	if dbCrypt.keys == nil {
+		dbCrypt.keys = make(map[string][]byte)
+	}
+	dbCrypt.keys[string(salt)] = key
+	return key, nil
+}
+
+// prepareEncryption derives the key databases are written with, if
+// they're encrypted, so the passphrase is asked for before the game
+// starts rather than on its first save.
+func prepareEncryption() error {
+	dbCrypt.mu.Lock()
+	defer dbCrypt.mu.Unlock()
+	if !dbCrypt.encrypt || noSave {
+		return nil
+	}
+	_, err := writeKey()
+	return err
+}
+
+// writeKey returns the key databases are written with, reusing the salt
+// of a database already read. It's called with dbCrypt.mu held.
+func writeKey() ([]byte, error) {
+	if dbCrypt.salt == nil {
+		salt := make([]byte, saltSize)
+		rand.Read(salt)
+		dbCrypt.salt = salt
+	}
+	return storeKey(dbCrypt.salt)
+}
+
+// decryptStore returns a database file's JSON, decrypting it if it's
+// encrypted.
+func decryptStore(b []byte) ([]byte, error) {
+	if !isEncrypted(b) {
+		return b, nil
+	}
+	b = b[len(encryptedMagic):]
+	if len(b) < saltSize {
+		return nil, errors.New("the encrypted database is truncated")
+	}
+	salt, sealed := b[:saltSize], b[saltSize:]
+
+	dbCrypt.mu.Lock()
+	defer dbCrypt.mu.Unlock()
+	key, err := storeKey(salt)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("the encrypted database is truncated")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(encryptedMagic))
+	if err != nil {
+		return nil, errors.New("can't decrypt the database: wrong passphrase or key file?")
+	}
+	if dbCrypt.salt == nil {
+		dbCrypt.salt = bytes.Clone(salt)
+	}
+	return plain, nil
+}
+
+// encryptStore encrypts a database's JSON for writing, if databases are
+// encrypted.
+func encryptStore(b []byte) ([]byte, error) {
+	dbCrypt.mu.Lock()
+	defer dbCrypt.mu.Unlock()
+	if !dbCrypt.encrypt {
+		return b, nil
+	}
+	key, err := writeKey()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte(encryptedMagic), dbCrypt.salt...)
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, b, []byte(encryptedMagic)), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptStore(t *testing.T) {
+	t.Cleanup(func() { setupEncryption(false, "") })
+	plain := []byte(`{"games":{"2025-03-01":{"correct":10}}}`)
+
+	keyFile := filepath.Join(t.TempDir(), "brack.key")
+	if err := os.WriteFile(keyFile, []byte("correct horse battery staple\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name, keyFile, passphrase string
+	}{
+		{name: "passphrase", passphrase: "hunter2"},
+		{name: "key file", keyFile: keyFile},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(passphraseEnv, tc.passphrase)
+			setupEncryption(true, tc.keyFile)
+			sealed, err := encryptStore(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isEncrypted(sealed) || bytes.Contains(sealed, []byte("games")) {
+				t.Fatalf("the database isn't encrypted: %q", sealed)
+			}
+
+			// Read back as a new process would, deriving the key again
+			setupEncryption(false, tc.keyFile)
+			got, err := decryptStore(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Errorf("decrypted %q, want %q", got, plain)
+			}
+
+			// Unencrypted databases are read as they are
+			if got, err := decryptStore(plain); err != nil || !bytes.Equal(got, plain) {
+				t.Errorf("reading an unencrypted database: %q, %v", got, err)
+			}
+
+			setupEncryption(false, "")
+			t.Setenv(passphraseEnv, "hunter3")
+			if _, err := decryptStore(sealed); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+				t.Errorf("decrypting with the wrong passphrase: %v", err)
+			}
+			setupEncryption(false, "")
+			if _, err := decryptStore(sealed[:len(encryptedMagic)+4]); err == nil {
+				t.Error("a truncated database was decrypted")
+			}
+		})
+	}
+}
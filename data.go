@@ -2,12 +2,29 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
 
-const endpoint = "https://8huadblp0h.execute-api.us-east-2.amazonaws.com/puzzles"
+const defaultEndpoint = "https://8huadblp0h.execute-api.us-east-2.amazonaws.com/puzzles"
+
+// endpoint is the URL of the puzzle API, which can be overridden in the
+// config (e.g. to point at a mirror or a local server).
+var endpoint = defaultEndpoint
+
+// setEndpoint sets the puzzle API's URL from the config, if it's set
+// there.
+func setEndpoint(url string) {
+	if url != "" {
+		endpoint = strings.TrimSuffix(url, "/")
+	}
+}
 
 type puzzledata struct {
 	CompletionText string            `json:"completionText"`
@@ -18,19 +35,132 @@ type puzzledata struct {
 	PuzzleSolution string            `json:"puzzleSolution"`
 }
 
-func getPuzzleData(d time.Time) (puzzledata, error) {
-	url := endpoint + "/" + d.Format("2006-01-02")
-	resp, err := http.Get(url)
+func puzzleURL(d time.Time) string {
+	return endpoint + "/" + d.Format("2006-01-02")
+}
+
+// getPuzzleData fetches the puzzle for a date, returning the API's raw
+// response along with it.
+func getPuzzleData(d time.Time) (puzzledata, json.RawMessage, error) {
+	url := puzzleURL(d)
+	slog.Debug("fetching puzzle", "url", url)
+	start := time.Now()
+	resp, err := getPolitely(url)
 	if err != nil {
-		return puzzledata{}, err
+		slog.Error("failed to fetch puzzle", "url", url, "err", err)
+		return puzzledata{}, nil, err
 	}
 	defer resp.Body.Close()
+	slog.Debug("fetched puzzle", "url", url, "status", resp.StatusCode, "elapsed", time.Since(start))
+	if resp.StatusCode != http.StatusOK {
+		return puzzledata{}, nil, fmt.Errorf("failed to fetch puzzle for %s: %s", d.Format(time.DateOnly), resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return puzzledata{}, nil, err
+	}
+	puzzle, err := decodePuzzle(b)
+	if err != nil {
+		slog.Error("failed to decode puzzle", "url", url, "err", err)
+		return puzzledata{}, nil, fmt.Errorf("puzzle for %s: %w", d.Format(time.DateOnly), err)
+	}
+	return puzzle, b, nil
+}
+
+// errUnsupportedPuzzle is returned for puzzles in a format brack
+// doesn't understand, which probably means the API has changed.
+var errUnsupportedPuzzle = errors.New("unsupported puzzle format, please upgrade brack (brack upgrade)")
 
-	var puzzle puzzledata
-	if err := json.NewDecoder(resp.Body).Decode(&puzzle); err != nil {
+// decodePuzzle decodes a puzzle from the API. Fields brack doesn't know
+// about are ignored, but the ones it needs must be there, with the
+// right types, so that a change to the API's format fails clearly
+// rather than leaving a blank puzzle.
+func decodePuzzle(b []byte) (puzzledata, error) {
+	var p puzzledata
+	if err := json.Unmarshal(b, &p); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return puzzledata{}, fmt.Errorf("%w: %v", errUnsupportedPuzzle, err)
+		}
+		return puzzledata{}, err
+	}
+	if err := p.validate(); err != nil {
 		return puzzledata{}, err
 	}
-	return puzzle, nil
+	return p, nil
+}
+
+// validate checks the puzzle has everything needed to play it.
+func (pd puzzledata) validate() error {
+	var missing []string
+	if pd.PuzzleDate == "" {
+		missing = append(missing, "puzzleDate")
+	}
+	if pd.InitialPuzzle == "" {
+		missing = append(missing, "initialPuzzle")
+	}
+	if len(pd.Solutions) == 0 {
+		missing = append(missing, "solutions")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", errUnsupportedPuzzle, strings.Join(missing, ", "))
+	}
+	if len(getActiveQuestions(pd, pd.InitialPuzzle)) == 0 {
+		return fmt.Errorf("%w: none of the solutions match a clue", errUnsupportedPuzzle)
+	}
+	return nil
+}
+
+// dailyFetchBudget is the most puzzles brack will fetch from the API
+// in a day, so that nothing (e.g. stepping through the calendar) can
+// hammer it.
+const dailyFetchBudget = 100
+
+// Metadata keys for the fetch budget: the day it's for, and how many
+// puzzles have been fetched that day.
+const (
+	metaFetchDay   = "fetch_budget.day"
+	metaFetchCount = "fetch_budget.count"
+)
+
+// spendFetch counts a fetch against today's budget, returning an error
+// if it's been used up.
+func (s *store) spendFetch() error {
+	day := time.Now().Format(time.DateOnly)
+	n := 0
+	if s.meta(metaFetchDay) == day {
+		n, _ = strconv.Atoi(s.meta(metaFetchCount))
+	}
+	if n >= dailyFetchBudget {
+		return fmt.Errorf("already fetched %d puzzles today, try again tomorrow", n)
+	}
+	if err := s.setMeta(metaFetchDay, day); err != nil {
+		return err
+	}
+	return s.setMeta(metaFetchCount, strconv.Itoa(n+1))
+}
+
+// loadPuzzle returns the puzzle for a date from the store's cache,
+// fetching (and caching) it if it isn't there.
+func loadPuzzle(s *store, d time.Time) (puzzledata, error) {
+	date := d.Format(time.DateOnly)
+	if p, ok := s.puzzle(date); ok {
+		slog.Debug("using cached puzzle", "date", date)
+		return p, nil
+	}
+
+	if err := s.spendFetch(); err != nil {
+		return puzzledata{}, err
+	}
+	p, raw, err := getPuzzleData(d)
+	if err != nil {
+		return puzzledata{}, err
+	}
+	if err := s.savePuzzle(date, p, raw); err != nil {
+		slog.Error("failed to cache puzzle", "date", date, "err", err)
+	}
+	return p, nil
 }
 
 func getActiveQuestions(pd puzzledata, s string) map[string]string {
@@ -42,3 +172,18 @@ func getActiveQuestions(pd puzzledata, s string) map[string]string {
 	}
 	return qs
 }
+
+// wordCount returns the number of words in the puzzle's text, with
+// all of its clues showing.
+func (pd puzzledata) wordCount() int {
+	s := strings.NewReplacer("[", " ", "]", " ").Replace(pd.InitialPuzzle)
+	return len(strings.Fields(s))
+}
+
+// readingTime estimates how long it takes to read the puzzle through
+// once, at 200 words per minute.
+func (pd puzzledata) readingTime() time.Duration {
+	const wpm = 200
+	d := time.Duration(pd.wordCount()) * time.Minute / wpm
+	return max(d.Round(time.Minute), time.Minute)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed demo/puzzle.json
+var demoPuzzleJSON []byte
+
+// demoscript is a scripted sequence of steps to replay against the
+// bundled demo puzzle.
+type demoscript struct {
+	Width  int           `yaml:"width"`
+	Height int           `yaml:"height"`
+	Typing time.Duration `yaml:"typing"`
+	Steps  []demostep    `yaml:"steps"`
+}
+
+// demostep is a single step in a demo script. Only one of its fields
+// should be set.
+type demostep struct {
+	Sleep time.Duration `yaml:"sleep"`
+	Type  string        `yaml:"type"`
+	Key   string        `yaml:"key"`
+	Keys  []string      `yaml:"keys"`
+}
+
+func loadDemoPuzzle() (puzzledata, error) {
+	return decodePuzzle(demoPuzzleJSON)
+}
+
+func loadDemoScript(path string) (demoscript, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return demoscript{}, err
+	}
+
+	var ds demoscript
+	if err := yaml.Unmarshal(b, &ds); err != nil {
+		return demoscript{}, fmt.Errorf("invalid demo script %q: %w", path, err)
+	}
+	if ds.Typing <= 0 {
+		ds.Typing = 80 * time.Millisecond
+	}
+
+	// Check the keys up front, rather than failing mid-recording
+	for i, s := range ds.Steps {
+		for _, k := range s.keys() {
+			if _, err := parseKey(k); err != nil {
+				return demoscript{}, fmt.Errorf("demo script step %d: %w", i+1, err)
+			}
+		}
+	}
+	return ds, nil
+}
+
+func (s demostep) keys() []string {
+	if s.Key != "" {
+		return append([]string{s.Key}, s.Keys...)
+	}
+	return s.Keys
+}
+
+// play replays the script's steps against the running program, then
+// quits it.
+func (ds demoscript) play(p *tea.Program) {
+	if ds.Width > 0 && ds.Height > 0 {
+		p.Send(tea.WindowSizeMsg{Width: ds.Width, Height: ds.Height})
+	}
+
+	for _, s := range ds.Steps {
+		time.Sleep(s.Sleep)
+		for _, msg := range typeText(s.Type) {
+			p.Send(msg)
+			time.Sleep(ds.Typing)
+		}
+		for _, k := range s.keys() {
+			msg, _ := parseKey(k) // Already validated
+			p.Send(msg)
+		}
+	}
+	p.Quit()
+}
+
+func runDemo(script string) error {
+	ds, err := loadDemoScript(script)
+	if err != nil {
+		return err
+	}
+	puzzle, err := loadDemoPuzzle()
+	if err != nil {
+		return err
+	}
+
+	setLocale("")
+
+	// Run the puzzle, driven by the script
+	cfg := defaultConfig()
+	cfg.CheckForUpdates = false
+	p := tea.NewProgram(newRouter(newModel(puzzle, nil, cfg)), tea.WithAltScreen())
+	go ds.play(p)
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var dialogStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(colorYellow).
+	Padding(1, 3)
+
+// dialog is a modal question with a choice of answers, shown over the
+// game until one is chosen. Each choice can also be picked by its
+// first letter.
+type dialog struct {
+	question string
+	choices  []string
+	focus    int
+
+	// choose is called with the index of the chosen answer, or -1 if
+	// the dialog was dismissed.
+	choose func(m model, i int) (model, tea.Cmd)
+}
+
+// confirmAction asks a yes/no question, doing the action on yes. No
+// has the focus to start with, since the actions are destructive.
+func (m *model) confirmAction(question string, action func(m model) (model, tea.Cmd)) {
+	m.dialog = &dialog{
+		question: question,
+		choices:  []string{tr("Yes"), tr("No")},
+		focus:    1,
+		choose: func(m model, i int) (model, tea.Cmd) {
+			if i != 0 {
+				return m, nil
+			}
+			return action(m)
+		},
+	}
+}
+
+// updateDialog handles keys while a dialog is open.
+func (m model) updateDialog(msg tea.KeyMsg) (model, tea.Cmd) {
+	d := *m.dialog
+	switch {
+	case key.Matches(msg, keys.Dismiss):
+		m.dialog = nil
+		return d.choose(m, -1)
+	case key.Matches(msg, keys.Submit):
+		m.dialog = nil
+		return d.choose(m, d.focus)
+	case key.Matches(msg, keys.Left), msg.Type == tea.KeyShiftTab:
+		d.focus = (d.focus + len(d.choices) - 1) % len(d.choices)
+	case key.Matches(msg, keys.Right), msg.Type == tea.KeyTab:
+		d.focus = (d.focus + 1) % len(d.choices)
+	case msg.Type == tea.KeyRunes && len(msg.Runes) == 1:
+		for i, c := range d.choices {
+			if unicode.ToLower([]rune(c)[0]) == unicode.ToLower(msg.Runes[0]) {
+				m.dialog = nil
+				return d.choose(m, i)
+			}
+		}
+	}
+	m.dialog = &d
+	return m, nil
+}
+
+// dialogView renders the open dialog in the middle of the window.
+func (m model) dialogView() string {
+	var buttons []string
+	for i, c := range m.dialog.choices {
+		if i == m.dialog.focus {
+			buttons = append(buttons, activeStyle.Render(" "+c+" "))
+		} else {
+			buttons = append(buttons, noticeStyle.Render(" "+c+" "))
+		}
+	}
+	box := dialogStyle.Render(lipgloss.JoinVertical(lipgloss.Center,
+		m.dialog.question,
+		"",
+		strings.Join(buttons, "   "),
+	))
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultDictionaryURL is the web API used to look up words when no
+// dictionary command is configured. %s is replaced with the word.
+const defaultDictionaryURL = "https://api.dictionaryapi.dev/api/v2/entries/en/%s"
+
+// maxDefinitions is how many definitions are shown for a word.
+const maxDefinitions = 3
+
+// definitionMsg is the result of looking up a word.
+type definitionMsg struct {
+	word string
+	text string
+	err  error
+}
+
+// lookupWord looks up a word with the configured dictionary command
+// (e.g. "dict -d wn", run with the word as its last argument), or the
+// dictionary web API otherwise.
+func lookupWord(cfg config, word string) tea.Cmd {
+	return func() tea.Msg {
+		var text string
+		var err error
+		if cfg.DictionaryCommand != "" {
+			text, err = lookupWithCommand(cfg.DictionaryCommand, word)
+		} else {
+			text, err = lookupWithAPI(cfg.DictionaryURL, word)
+		}
+		if err != nil {
+			slog.Error("failed to look up word", "word", word, "err", err)
+		}
+		return definitionMsg{word: word, text: text, err: err}
+	}
+}
+
+func lookupWithCommand(command, word string) (string, error) {
+	args := strings.Fields(command)
+	out, err := exec.Command(args[0], append(args[1:], word)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func lookupWithAPI(endpoint, word string) (string, error) {
+	if endpoint == "" {
+		endpoint = defaultDictionaryURL
+	}
+	resp, err := httpClient.Get(fmt.Sprintf(endpoint, url.PathEscape(word)))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("dictionary lookup failed: %s", resp.Status)
+	}
+
+	var entries []struct {
+		Meanings []struct {
+			PartOfSpeech string `json:"partOfSpeech"`
+			Definitions  []struct {
+				Definition string `json:"definition"`
+			} `json:"definitions"`
+		} `json:"meanings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return "", err
+	}
+	var defs []string
+	for _, e := range entries {
+		for _, mn := range e.Meanings {
+			for _, d := range mn.Definitions {
+				if len(defs) < maxDefinitions {
+					defs = append(defs, fmt.Sprintf("%d. (%s) %s", len(defs)+1, mn.PartOfSpeech, d.Definition))
+				}
+			}
+		}
+	}
+	return strings.Join(defs, "\n"), nil
+}
+
+// lookupWords are the words that can be looked up: the puzzle's
+// answers, in alphabetical order.
+func (m model) lookupWords() []string {
+	var ws []string
+	for _, a := range m.data.Solutions {
+		if !slices.Contains(ws, a) {
+			ws = append(ws, a)
+		}
+	}
+	slices.Sort(ws)
+	return ws
+}
+
+// updateLookup handles keys while the lookup panel is open.
+func (m model) updateLookup(msg tea.KeyMsg) (model, tea.Cmd) {
+	ws := m.lookupWords()
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.lookupIdx = (m.lookupIdx + len(ws) - 1) % len(ws)
+	case key.Matches(msg, keys.Down):
+		m.lookupIdx = (m.lookupIdx + 1) % len(ws)
+	case key.Matches(msg, keys.Submit):
+		m.definition = tr("Looking up %s...", ws[m.lookupIdx])
+		return m, lookupWord(m.cfg, ws[m.lookupIdx])
+	case key.Matches(msg, keys.Close, keys.Lookup):
+		m.lookup = false
+		m.definition = ""
+	}
+	return m, nil
+}
+
+// lookupView renders the lookup panel.
+func (m model) lookupView() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("[ Bracket City | "+m.data.PuzzleDate+" ]") + "\n\n")
+	b.WriteString(headerStyle.Render(tr("Look up a word")) + "\n")
+	for i, w := range m.lookupWords() {
+		if i == m.lookupIdx {
+			b.WriteString(activeStyle.Render("> "+w) + "\n")
+		} else {
+			b.WriteString("  " + w + "\n")
+		}
+	}
+	if m.definition != "" {
+		b.WriteString("\n" + lipgloss.NewStyle().Width(m.bodyWidth()).Render(m.definition) + "\n")
+	}
+	look, back := keys.Submit, keys.Close
+	look.SetHelp("enter", "look up")
+	back.SetHelp("esc", "back")
+	b.WriteString("\n" + noticeStyle.Render(helpLine(keys.Up, keys.Down, look, back)))
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the solution diff: words only in the solution (answers
+// that were revealed rather than solved), and words only in the final
+// state (the clues left unsolved).
+var (
+	revealedStyle = lipgloss.NewStyle().
+			Foreground(colorInk).
+			Background(colorRed)
+	unsolvedStyle = lipgloss.NewStyle().
+			Faint(true).
+			Strikethrough(true)
+)
+
+// diffWords compares the final state of a puzzle to its solution, word
+// by word, rendering the solution with the differences highlighted.
+func diffWords(state, solution string) string {
+	a, b := strings.Fields(state), strings.Fields(solution)
+
+	// Longest common subsequence of the words
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Walk it, styling each word on its own so the result can be
+	// wrapped between words
+	var words []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			words = append(words, b[j])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			// An unsolved clue, followed by the answer it hid
+			words = append(words, unsolvedStyle.Render(a[i]))
+			i++
+		default:
+			words = append(words, revealedStyle.Render(b[j]))
+			j++
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// diffView renders the puzzle's solution compared to the final state.
+func (m model) diffView() string {
+	if m.state == m.data.PuzzleSolution {
+		return m.body + "\n\n" + noticeStyle.Render(tr("Every answer was solved."))
+	}
+	return lipgloss.NewStyle().Width(m.bodyWidth()).Render(diffWords(m.state, m.data.PuzzleSolution)) +
+		"\n\n" + noticeStyle.Render(tr("%s: revealed · %s: left unsolved",
+		revealedStyle.Render(tr("highlighted")),
+		unsolvedStyle.Render(tr("struck through")),
+	))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// difficulty is an estimate of how hard a puzzle is, from its structure
+// and, when there's enough history, how the player has done on similar
+// puzzles.
+type difficulty struct {
+	// Stars rates the puzzle from 1 (easy) to 5 (hard).
+	Stars int
+
+	// Typical is the average number of incorrect guesses on the
+	// most similar puzzles solved before, if there are any.
+	Typical    float64
+	HasTypical bool
+}
+
+// minHistory is how many cached puzzles are needed to rate a puzzle
+// relative to the others, rather than on a fixed scale.
+const minHistory = 5
+
+// depth is how deeply the puzzle's brackets are nested.
+func (d puzzledata) depth() int {
+	depth, deepest := 0, 0
+	for _, r := range d.InitialPuzzle {
+		switch r {
+		case '[':
+			depth++
+			deepest = max(deepest, depth)
+		case ']':
+			depth--
+		}
+	}
+	return deepest
+}
+
+// complexity scores a puzzle's structure: more clues, deeper nesting and
+// longer answers make for a harder puzzle.
+func (d puzzledata) complexity() float64 {
+	var letters int
+	for _, a := range d.Solutions {
+		letters += utf8.RuneCountInString(strings.ReplaceAll(a, " ", ""))
+	}
+	avg := 0.0
+	if len(d.Solutions) > 0 {
+		avg = float64(letters) / float64(len(d.Solutions))
+	}
+	return 0.1*float64(len(d.Solutions)) + 0.5*float64(d.depth()) + 0.1*avg
+}
+
+// estimateDifficulty rates a puzzle. With enough cached puzzles it's
+// rated against them, and the player's results on the closest ones
+// give a typical number of incorrect guesses. The store may be nil.
+func estimateDifficulty(d puzzledata, s *store) difficulty {
+	c := d.complexity()
+	diff := difficulty{Stars: clampStars(c)}
+	if s == nil {
+		return diff
+	}
+
+	// Rate it against the other puzzles
+	others := s.cachedPuzzles()
+	if len(others) < minHistory {
+		return diff
+	}
+	var below int
+	for _, p := range others {
+		if p.complexity() < c {
+			below++
+		}
+	}
+	diff.Stars = 1 + int(math.Round(4*float64(below)/float64(len(others))))
+
+	// How did the player do on the most similar solved puzzles?
+	type result struct {
+		dist      float64
+		incorrect int
+	}
+	var results []result
+	for _, p := range others {
+		gs, ok := s.game(p.PuzzleDate)
+		if !ok || !gs.Done || gs.GaveUp || p.PuzzleDate == d.PuzzleDate {
+			continue
+		}
+		results = append(results, result{math.Abs(p.complexity() - c), gs.Incorrect})
+	}
+	if len(results) == 0 {
+		return diff
+	}
+	slices.SortFunc(results, func(a, b result) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+	results = results[:min(len(results), minHistory)]
+	var sum int
+	for _, r := range results {
+		sum += r.incorrect
+	}
+	diff.Typical = float64(sum) / float64(len(results))
+	diff.HasTypical = true
+	return diff
+}
+
+// clampStars maps a complexity score onto 1 to 5 stars.
+func clampStars(c float64) int {
+	return min(max(int(math.Round(c)), 1), 5)
+}
+
+// String shows the rating as stars, e.g. "★★★☆☆".
+func (d difficulty) String() string {
+	return strings.Repeat("★", d.Stars) + strings.Repeat("☆", 5-d.Stars)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// discordAPI is the base URL of Discord's REST API.
+const discordAPI = "https://discord.com/api/v10"
+
+// Discord's interaction and response types (see
+// https://discord.com/developers/docs/interactions/receiving-and-responding).
+const (
+	discordPing           = 1
+	discordCommand        = 2
+	discordPong           = 1
+	discordMessage        = 4
+	discordEphemeral      = 1 << 6
+	discordSubcommand     = 1
+	discordStringArgument = 3
+)
+
+// discordCommands are the slash commands the bot registers: /brack show
+// and /brack guess ANSWER.
+var discordCommands = []any{
+	map[string]any{
+		"name":        "brack",
+		"description": "Play today's Bracket City puzzle with the channel",
+		"options": []any{
+			map[string]any{
+				"type":        discordSubcommand,
+				"name":        "show",
+				"description": "Show the channel's game",
+			},
+			map[string]any{
+				"type":        discordSubcommand,
+				"name":        "guess",
+				"description": "Guess an answer",
+				"options": []any{
+					map[string]any{
+						"type":        discordStringArgument,
+						"name":        "answer",
+						"description": "Your answer to one of the clues",
+						"required":    true,
+					},
+				},
+			},
+		},
+	},
+}
+
+type discordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type discordOption struct {
+	Name    string          `json:"name"`
+	Value   any             `json:"value"`
+	Options []discordOption `json:"options"`
+}
+
+// discordInteraction is the part of an interaction the bot uses. In a
+// server, the user is in member, and in a DM, in user.
+type discordInteraction struct {
+	Type      int    `json:"type"`
+	ChannelID string `json:"channel_id"`
+	Data      struct {
+		Name    string          `json:"name"`
+		Options []discordOption `json:"options"`
+	} `json:"data"`
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"`
+}
+
+// discordBot answers /brack commands sent to its interactions endpoint,
+// each channel playing its own game of today's puzzle.
+type discordBot struct {
+	key   ed25519.PublicKey
+	games *channelGames
+}
+
+// discordRequest makes a request to Discord's API as the bot,
+// decoding the response into out if it isn't nil.
+func discordRequest(method, path, token string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, discordAPI+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bot "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord: %s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// setupDiscord looks up the bot's application, for the public key its
+// interactions are signed with, and registers the slash commands.
+func setupDiscord(token string) (ed25519.PublicKey, error) {
+	var app struct {
+		ID        string `json:"id"`
+		VerifyKey string `json:"verify_key"`
+	}
+	if err := discordRequest(http.MethodGet, "/applications/@me", token, nil, &app); err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(app.VerifyKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("discord: invalid public key %q", app.VerifyKey)
+	}
+	if err := discordRequest(http.MethodPut, "/applications/"+app.ID+"/commands", token, discordCommands, nil); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (b *discordBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "can't read the request", http.StatusBadRequest)
+		return
+	}
+	// Discord checks that unsigned requests are turned away
+	sig, err := hex.DecodeString(r.Header.Get("X-Signature-Ed25519"))
+	msg := append([]byte(r.Header.Get("X-Signature-Timestamp")), body...)
+	if err != nil || !ed25519.Verify(b.key, msg, sig) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var i discordInteraction
+	if err := json.Unmarshal(body, &i); err != nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+	switch i.Type {
+	case discordPing:
+		writeJSON(w, http.StatusOK, map[string]int{"type": discordPong})
+	case discordCommand:
+		content, ephemeral := b.run(i)
+		data := map[string]any{
+			"content":          content,
+			"allowed_mentions": map[string]any{"parse": []string{}},
+		}
+		if ephemeral {
+			data["flags"] = discordEphemeral
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"type": discordMessage, "data": data})
+	default:
+		http.Error(w, "unsupported interaction", http.StatusBadRequest)
+	}
+}
+
+// run runs a /brack command, returning the reply, and whether only
+// the user who sent it should see it (e.g. for errors).
+func (b *discordBot) run(i discordInteraction) (string, bool) {
+	if i.Data.Name != "brack" || len(i.Data.Options) != 1 {
+		return "Unknown command, try /brack show or /brack guess", true
+	}
+	e, err := b.games.engine(i.ChannelID)
+	if err != nil {
+		slog.Error("failed to open the channel's games", "channel", i.ChannelID, "err", err)
+		return "Couldn't open this channel's game: " + err.Error(), true
+	}
+
+	who := "Someone"
+	if u := i.User; u != nil {
+		who = "<@" + u.ID + ">"
+	} else if i.Member != nil {
+		who = "<@" + i.Member.User.ID + ">"
+	}
+
+	sub := i.Data.Options[0]
+	switch sub.Name {
+	case "show":
+		g, err := e.game(today())
+		if err != nil {
+			return err.Error(), true
+		}
+		return chatGame(g), false
+	case "guess":
+		var answer string
+		for _, o := range sub.Options {
+			if s, ok := o.Value.(string); ok && o.Name == "answer" {
+				answer = s
+			}
+		}
+		res, err := e.guess(today(), answer)
+		if err != nil {
+			return chatError(err), true
+		}
+		return chatGuess(who, res), false
+	}
+	return "Unknown command, try /brack show or /brack guess", true
+}
+
+// runDiscordBot registers the bot's commands and serves its
+// interactions endpoint on addr until interrupted.
+func runDiscordBot(ctx context.Context, w io.Writer, addr string, cfg config) error {
+	if cfg.DiscordToken == "" {
+		return errors.New("no Discord bot token, set discord_token in the config file")
+	}
+	key, err := setupDiscord(cfg.DiscordToken)
+	if err != nil {
+		return err
+	}
+	bot := &discordBot{key: key, games: newChannelGames("discord", cfg)}
+	return serveUntilInterrupted(ctx, w, addr, "the Discord interactions endpoint", bot)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+// runDoctor writes a report of environment diagnostics, for
+// troubleshooting and to attach to bug reports.
+func runDoctor(w io.Writer) error {
+	fmt.Fprintf(w, "brack %s (%s %s/%s)\n", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	// Config
+	fmt.Fprintln(w, "\nConfig:")
+	if path, err := configPath(); err != nil {
+		fmt.Fprintf(w, "  ✗ can't determine config path: %s\n", err)
+	} else if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(w, "  path:   %s (not found, using defaults)\n", path)
+	} else if _, err := loadConfig(path); err != nil {
+		fmt.Fprintf(w, "  path:   %s\n", path)
+		fmt.Fprintf(w, "  ✗ %s\n", err)
+	} else {
+		fmt.Fprintf(w, "  path:   %s\n", path)
+		fmt.Fprintln(w, "  ✓ valid")
+	}
+	for _, o := range overrides {
+		if v := os.Getenv(o.env); v != "" {
+			fmt.Fprintf(w, "  $%s=%s\n", o.env, v)
+		}
+	}
+
+	// Store
+	fmt.Fprintln(w, "\nDatabase:")
+	cfg, _ := loadUserConfig() // Reported above
+	if path, err := storePath(cfg); err != nil {
+		fmt.Fprintf(w, "  ✗ can't determine database path: %s\n", err)
+	} else if path == memoryPath {
+		fmt.Fprintf(w, "  path:   %s (kept in memory, nothing is saved)\n", path)
+	} else {
+		fmt.Fprintf(w, "  path:   %s\n", path)
+		if fi, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(w, "  (not created yet)")
+		} else if err != nil {
+			fmt.Fprintf(w, "  ✗ %s\n", err)
+		} else if s, err := openStore(path); err != nil {
+			fmt.Fprintf(w, "  size:   %d bytes%s\n", fi.Size(), encryptedNote(path))
+			fmt.Fprintf(w, "  ✗ can't read database: %s\n", err)
+		} else {
+			fmt.Fprintf(w, "  size:   %d bytes%s\n", fi.Size(), encryptedNote(path))
+			fmt.Fprintf(w, "  schema: v%d (current: v%d)\n", s.data.Version, storeVersion)
+			fmt.Fprintf(w, "  games:  %d\n", len(s.data.Games))
+		}
+	}
+
+	// API
+	fmt.Fprintln(w, "\nAPI:")
+	url := puzzleURL(time.Now())
+	fmt.Fprintf(w, "  url:    %s\n", url)
+	start := time.Now()
+	if resp, err := httpClient.Get(url); err != nil {
+		fmt.Fprintf(w, "  ✗ unreachable: %s\n", err)
+	} else {
+		resp.Body.Close()
+		fmt.Fprintf(w, "  status: %s\n", resp.Status)
+		fmt.Fprintf(w, "  time:   %s\n", time.Since(start).Round(time.Millisecond))
+	}
+
+	// Terminal
+	fmt.Fprintln(w, "\nTerminal:")
+	fmt.Fprintf(w, "  TERM:      %s\n", os.Getenv("TERM"))
+	fmt.Fprintf(w, "  COLORTERM: %s\n", os.Getenv("COLORTERM"))
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		fmt.Fprintln(w, "  (stdout is not a terminal)")
+		return nil
+	}
+	fmt.Fprintf(w, "  colors:    %s\n", lipgloss.ColorProfile().Name())
+	if tw, th, err := term.GetSize(os.Stdout.Fd()); err != nil {
+		fmt.Fprintf(w, "  ✗ can't get size: %s\n", err)
+	} else {
+		fmt.Fprintf(w, "  size:      %dx%d\n", tw, th)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errInvalidDate and errEmptyAnswer are mistakes in a front end's
+// request to the engine.
+var (
+	errInvalidDate = errors.New("invalid date")
+	errEmptyAnswer = errors.New("the answer is empty")
+)
+
+// coolingError is a guess made during the hard mode cooldown after a
+// wrong one.
+type coolingError struct {
+	left time.Duration
+}
+
+func (e coolingError) Error() string {
+	return fmt.Sprintf("cooling down after a wrong guess, wait %s", e.left.Round(time.Second))
+}
+
+// engine plays the games in a store for front ends other than the TUI
+// (see brack serve and brack rpc). Each call opens the game afresh, as
+// brack guess does, so it picks up games played in the TUI meanwhile.
+//
+// A date is YYYY-MM-DD, or a negative number of days back from today.
+type engine struct {
+	cfg config
+
+	// mu serializes calls, as the store isn't safe for concurrent use
+	mu    sync.Mutex
+	store *store
+
+	// cooling is when each date's hard mode cooldown ends, as it isn't
+	// saved with the game
+	cooling map[string]time.Time
+}
+
+// puzzleReport is a puzzle as given to front ends, without its
+// answers.
+type puzzleReport struct {
+	Date   string `json:"date"`
+	Puzzle string `json:"puzzle"`
+	Clues  int    `json:"clues"`
+}
+
+func newEngine(s *store, cfg config) *engine {
+	return &engine{
+		cfg:     cfg,
+		store:   s,
+		cooling: make(map[string]time.Time),
+	}
+}
+
+// load returns the puzzle for a date, from the cache or fetched. The
+// caller holds mu.
+func (e *engine) load(date string) (puzzledata, error) {
+	d, err := parseDateArg(date)
+	if err != nil || date == "" {
+		return puzzledata{}, fmt.Errorf("%w %q", errInvalidDate, date)
+	}
+	// Take in any games played in the TUI since the last call
+	e.store.mergeFromDisk()
+	return loadPuzzle(e.store, d)
+}
+
+// puzzle returns the puzzle for a date.
+func (e *engine) puzzle(date string) (puzzleReport, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	p, err := e.load(date)
+	if err != nil {
+		return puzzleReport{}, err
+	}
+	return puzzleReport{
+		Date:   p.PuzzleDate,
+		Puzzle: p.InitialPuzzle,
+		Clues:  len(p.Solutions),
+	}, nil
+}
+
+// game returns the state of the game for a date.
+func (e *engine) game(date string) (gameReport, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	p, err := e.load(date)
+	if err != nil {
+		return gameReport{}, err
+	}
+	return newModel(p, e.store, e.cfg).report(), nil
+}
+
+// guess makes a guess at the puzzle for a date.
+func (e *engine) guess(date, answer string) (guessResult, error) {
+	if strings.TrimSpace(answer) == "" {
+		return guessResult{}, errEmptyAnswer
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	p, err := e.load(date)
+	if err != nil {
+		return guessResult{}, err
+	}
+	if left := time.Until(e.cooling[p.PuzzleDate]); left > 0 {
+		return guessResult{}, coolingError{left}
+	}
+
+	d, _ := time.Parse(time.DateOnly, p.PuzzleDate)
+	m, err := openGame(e.store, e.cfg, d)
+	if err != nil {
+		return guessResult{}, err
+	}
+	m, r, err := m.guess(answer)
+	if err != nil {
+		return guessResult{}, err
+	}
+	if m.cooldownLeft() > 0 {
+		e.cooling[p.PuzzleDate] = m.cooldownUntil
+	}
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// gameEvent is something that happened during a game: a guess, a hint
+// or giving up. Each game keeps a log of them, for exporting.
+type gameEvent struct {
+	// At is when it happened, and Elapsed the solve time by then, in
+	// milliseconds.
+	At      time.Time `json:"at"`
+	Elapsed int64     `json:"elapsed_ms"`
+
+	// Kind is "correct", "incorrect", "hint" or "give_up".
+	Kind string `json:"kind"`
+
+	// Guess is what was guessed, and Clue the clue it answered (or
+	// that a hint was for), if any.
+	Guess string `json:"guess,omitempty"`
+	Clue  string `json:"clue,omitempty"`
+
+	// By is who made the guess, in a team session (see teamHost).
+	By string `json:"by,omitempty"`
+}
+
+// logEvent adds an event to the game's log.
+func (m *model) logEvent(kind, guess, clue string) {
+	m.events = append(m.events, gameEvent{
+		At:      time.Now(),
+		Elapsed: m.elapsedNow().Milliseconds(),
+		Kind:    kind,
+		Guess:   guess,
+		Clue:    clue,
+		By:      m.player,
+	})
+}
+
+// eventsExport is the JSON written by `brack export --events`.
+type eventsExport struct {
+	Date     string          `json:"date"`
+	Attempts []attemptEvents `json:"attempts"`
+}
+
+type attemptEvents struct {
+	Attempt int         `json:"attempt"`
+	Done    bool        `json:"done"`
+	GaveUp  bool        `json:"gave_up"`
+	Events  []gameEvent `json:"events"`
+}
+
+// scrubEvents strips what was guessed and the clues from events, which
+// would spoil the puzzle, leaving what happened when.
+func scrubEvents(events []gameEvent) []gameEvent {
+	out := make([]gameEvent, len(events))
+	for i, e := range events {
+		e.Guess, e.Clue = "", ""
+		out[i] = e
+	}
+	return out
+}
+
+// writeEvents writes the event logs of every attempt at the puzzle for
+// a date, oldest first, as JSON. Games from before events were logged
+// have none. With scrub, the guesses and clues are left out, so the
+// log can be shared without spoiling the puzzle.
+func writeEvents(w io.Writer, s *store, d time.Time, scrub bool) error {
+	date := d.Format(time.DateOnly)
+	games := s.attempts(date)
+	if gs, ok := s.game(date); ok {
+		games = append(games[:len(games):len(games)], gs)
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("you haven't played the puzzle for %s", date)
+	}
+
+	out := eventsExport{Date: date}
+	for i, gs := range games {
+		events := gs.Events
+		if scrub {
+			events = scrubEvents(events)
+		}
+		if events == nil {
+			events = []gameEvent{}
+		}
+		out.Attempts = append(out.Attempts, attemptEvents{
+			Attempt: i + 1,
+			Done:    gs.Done,
+			GaveUp:  gs.GaveUp,
+			Events:  events,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,26 @@
+package main
+
+import "strings"
+
+// innermostQuestions returns the active clues in state nested deepest
+// in brackets, with their answers. In expert mode, only these can be
+// answered: a puzzle is solved from the inside out, level by level.
+func innermostQuestions(pd puzzledata, state string) map[string]string {
+	depths := make(map[string]int)
+	deepest := 0
+	for _, loc := range clueRe.FindAllStringIndex(state, -1) {
+		before := state[:loc[0]]
+		depth := strings.Count(before, "[") - strings.Count(before, "]")
+		q := state[loc[0]+1 : loc[1]-1]
+		depths[q] = max(depths[q], depth)
+		deepest = max(deepest, depth)
+	}
+
+	qs := make(map[string]string)
+	for q, a := range getActiveQuestions(pd, state) {
+		if depths[q] == deepest {
+			qs[q] = a
+		}
+	}
+	return qs
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// metaFeedID is the metadata key of the feed's ID, made up the first
+// time it's exported, so readers see the same feed every time.
+const metaFeedID = "feed.id"
+
+// feedEntries is how many of the latest solves the feed has.
+const feedEntries = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Content atomText `xml:"content"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// formatUUID formats 16 bytes as a UUID, of the given version.
+func formatUUID(b []byte, version byte) string {
+	b[6] = b[6]&0x0f | version<<4
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// feedID returns the feed's ID, making one up the first time.
+func feedID(s *store) (string, error) {
+	if id := s.meta(metaFeedID); id != "" {
+		return id, nil
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := formatUUID(b, 4)
+	return id, s.setMeta(metaFeedID, id)
+}
+
+// solvedAt is when a game was solved, going by its last event, or the
+// puzzle's date for games from before events were logged.
+func solvedAt(gs gamestate) time.Time {
+	if n := len(gs.Events); n > 0 {
+		return gs.Events[n-1].At
+	}
+	t, _ := time.ParseInLocation(time.DateOnly, gs.Date, time.Local)
+	return t
+}
+
+// writeFeed writes an Atom feed of the latest solved puzzles, e.g. to
+// serve from a personal site. Entries have the share text, which
+// doesn't give away any answers, and don't link to the solved puzzles,
+// which do.
+func writeFeed(w io.Writer, s *store) error {
+	var games []gamestate
+	for _, gs := range s.allGames() {
+		if _, err := time.Parse(time.DateOnly, gs.Date); err == nil && gs.Done && !gs.GaveUp {
+			games = append(games, gs)
+		}
+	}
+	slices.SortFunc(games, func(a, b gamestate) int {
+		return solvedAt(b).Compare(solvedAt(a))
+	})
+	games = games[:min(len(games), feedEntries)]
+
+	id, err := feedID(s)
+	if err != nil {
+		return err
+	}
+	feed := atomFeed{
+		ID:      id,
+		Title:   "Bracket City results",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "brack"},
+	}
+	if len(games) > 0 {
+		feed.Updated = solvedAt(games[0]).UTC().Format(time.RFC3339)
+	}
+	for _, gs := range games {
+		// Replays of the same puzzle each get their own entry
+		at := solvedAt(gs)
+		sum := sha1.Sum([]byte(id + "/" + gs.Date + "/" + at.UTC().Format(time.RFC3339Nano)))
+		title := fmt.Sprintf("Bracket City %s: solved with %d incorrect", gs.Date, gs.Incorrect)
+		if gs.ElapsedSeconds > 0 {
+			title = fmt.Sprintf("Bracket City %s: solved in %s, %d incorrect", gs.Date,
+				formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second), gs.Incorrect)
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:      formatUUID(sum[:16], 5),
+			Title:   title,
+			Updated: at.UTC().Format(time.RFC3339),
+			Content: atomText{Type: "text", Text: strings.TrimSpace(shareText(gs, ""))},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
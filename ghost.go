@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// ghostOf returns the solve timeline of the fastest solved attempt
+// that has one, to race against when replaying a puzzle.
+func ghostOf(attempts []gamestate) ([]int64, bool) {
+	var best gamestate
+	for _, gs := range attempts {
+		if !gs.Done || gs.GaveUp || len(gs.SolveTimes) == 0 {
+			continue
+		}
+		if best.SolveTimes == nil || gs.ElapsedSeconds < best.ElapsedSeconds {
+			best = gs
+		}
+	}
+	return best.SolveTimes, best.SolveTimes != nil
+}
+
+// loadGhost sets up the ghost of the best earlier attempt at the
+// puzzle, if there is one and the game isn't over.
+func (m *model) loadGhost() {
+	m.ghost = nil
+	if m.store == nil || m.warmup || m.done {
+		return
+	}
+	if times, ok := ghostOf(m.store.attempts(m.data.PuzzleDate)); ok {
+		m.ghost = times
+	}
+}
+
+// ghostView shows how far the ghost had got by now, and whether you're
+// ahead of it or behind.
+func (m model) ghostView() string {
+	now := int64(m.elapsedNow() / time.Second)
+	ghostAt := sort.Search(len(m.ghost), func(i int) bool { return m.ghost[i] > now })
+	s := tr("👻 ghost %d/%d", ghostAt, len(m.ghost))
+
+	// Like splits in a race: while the ghost has solved more clues,
+	// you're behind by how long ago it solved the next one, otherwise
+	// compare your times for the last clue you solved
+	var d time.Duration
+	switch k := m.correct; {
+	case ghostAt > k:
+		d = time.Duration(now-m.ghost[k]) * time.Second
+	case k > 0 && k <= len(m.ghost) && k <= len(m.solveTimes):
+		d = time.Duration(m.solveTimes[k-1]-m.ghost[k-1]) * time.Second
+	}
+	switch {
+	case d < 0:
+		s += " · " + tr("%s ahead", formatElapsed(-d))
+	case d > 0:
+		s += " · " + errorStyle.Render(tr("%s behind", formatElapsed(d)))
+	}
+	return s
+}
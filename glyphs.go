@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// glyphs is the set of symbols the UI is drawn with: "emoji" (the
+// default), "text" for fonts that draw emoji at a different width than
+// they're measured at, which throws the layout out, or "ascii" for
+// consoles that can't draw anything else.
+var glyphs = "emoji"
+
+// emojiGlyphs are the emoji the UI uses, and text to use instead. Ones
+// that are only decoration are dropped, along with the space after
+// them.
+var emojiGlyphs = []string{
+	// The score
+	"✅", "OK", "❌", "ERR", "⌨️", "KEYS", "⏱️", "TIME",
+
+	// Decoration
+	"🛟 ", "", "🎓 ", "", "👻 ", "", "⏳ ", "", "🧩 ", "", "💡 ", "",
+	"🔒 ", "", "📡 ", "", "🧪 ", "", "👤 ", "", "🔐 ", "", "🔥 ", "", "🏅 ", "", "🏳️ ", "", "📝 ", "", "📖 ", "", "👥 ", "", "🏠 ", "",
+	"👉 ", "-> ",
+	"🎉", "*", "⚠️", "!", "⏸️", "||",
+}
+
+// textGlyphs replaces the UI's emoji with text, and asciiGlyphs its
+// other symbols too.
+var (
+	textGlyphs  = strings.NewReplacer(emojiGlyphs...)
+	asciiGlyphs = strings.NewReplacer(append(slices.Clone(emojiGlyphs), symbolGlyphs...)...)
+)
+
+// symbolGlyphs are the other symbols the UI uses, and ASCII to use
+// instead.
+var symbolGlyphs = []string{
+	"★", "*", "☆", ".", "■", "#", "•", "*", "·", "-", "—", "-", "–", "-",
+	"…", "...", "✓", "v", "✗", "x",
+	"↑", "up", "↓", "down", "←", "left", "→", "right",
+	"⁰", "0", "¹", "1", "²", "2", "³", "3", "⁴", "4",
+	"⁵", "5", "⁶", "6", "⁷", "7", "⁸", "8", "⁹", "9",
+
+	// Borders
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+", "─", "-", "│", "|",
+}
+
+// setGlyphs picks the glyph set from the config's settings. "auto"
+// (or no setting) uses ASCII in the legacy Windows console, and emoji
+// everywhere else, including Windows Terminal. noEmoji swaps emoji
+// for text.
+func setGlyphs(name string, noEmoji bool) error {
+	if err := checkGlyphs(name); err != nil {
+		return err
+	}
+	glyphs = name
+	if name == "" || name == "auto" {
+		glyphs = "emoji"
+		if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") == "" {
+			glyphs = "ascii"
+		}
+	}
+	if noEmoji && glyphs == "emoji" {
+		glyphs = "text"
+	}
+	return nil
+}
+
+// checkGlyphs returns an error for an unknown glyphs setting.
+func checkGlyphs(name string) error {
+	switch name {
+	case "", "auto", "emoji", "text", "ascii":
+		return nil
+	}
+	return fmt.Errorf("unknown glyphs setting %q (expected auto, emoji, text or ascii)", name)
+}
+
+// withGlyphs redraws a view with the chosen glyph set. The router does
+// it once for whichever screen is on top.
+func withGlyphs(v string) string {
+	switch glyphs {
+	case "text":
+		return textGlyphs.Replace(v)
+	case "ascii":
+		return asciiGlyphs.Replace(v)
+	}
+	return v
+}
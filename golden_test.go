@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestMain(m *testing.M) {
+	// Render without colors, so the snapshots are plain text
+	lipgloss.SetColorProfile(termenv.Ascii)
+	setLocale("")
+	os.Exit(m.Run())
+}
+
+// golden compares a view to testdata/NAME.golden, or rewrites it with
+// -update.
+func golden(t *testing.T, name, view string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(view), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if view != string(want) {
+		t.Errorf("%s doesn't match %s\ngot:\n%s\nwant:\n%s", name, path, view, want)
+	}
+}
+
+// newTestGame starts the bundled demo puzzle, without a database, with
+// the timer stopped so the views don't depend on how long a test takes.
+func newTestGame(t *testing.T) *driver {
+	t.Helper()
+	p, err := loadDemoPuzzle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := defaultConfig()
+	cfg.CheckForUpdates = false
+	m := newModel(p, nil, cfg)
+	m.stopTimer()
+	m.elapsed = 0
+	return newDriver(m).size(80, 24)
+}
+
+func TestGameView(t *testing.T) {
+	d := newTestGame(t)
+	golden(t, "game", d.view())
+
+	// A wrong guess, then a right one
+	d.typ("small")
+	if _, err := d.keys("enter"); err != nil {
+		t.Fatal(err)
+	}
+	d.typ("big")
+	if _, err := d.keys("enter"); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "game_progress", d.view())
+}
+
+func TestResultsView(t *testing.T) {
+	d := newTestGame(t)
+	for _, answer := range []string{"big", "bracket city", "terminal"} {
+		d.typ(answer)
+		if _, err := d.keys("enter"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m := d.m.(model); !m.done {
+		t.Fatalf("the puzzle isn't done after every answer: %q", m.state)
+	}
+	golden(t, "results", d.view())
+
+	if _, err := d.keys("d"); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "results_diff", d.view())
+
+	// In streamer mode, nothing shows the answers
+	for _, k := range []string{"ctrl+s", "d", "m", "l"} {
+		if _, err := d.keys(k); err != nil {
+			t.Fatal(err)
+		}
+		if v := d.view(); strings.Contains(v, "terminal.") || strings.Contains(v, "bracket city") {
+			t.Errorf("the answers are shown in streamer mode after %s:\n%s", k, v)
+		}
+	}
+	golden(t, "results_streamer", d.view())
+}
+
+func TestCalendarView(t *testing.T) {
+	s, err := openStore(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, gs := range []gamestate{
+		{Date: "2025-03-01", Done: true, Correct: 10},
+		{Date: "2025-03-02", Done: true, Correct: 10, Incorrect: 2},
+		{Date: "2025-03-04", Done: true, GaveUp: true, Correct: 4},
+		{Date: "2025-03-05", Correct: 3, ElapsedSeconds: 95},
+	} {
+		if err := s.saveGame(gs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	today := time.Date(2025, 3, 6, 0, 0, 0, 0, time.Local)
+	d := newDriver(newCalendarModel(s, today)).size(80, 24)
+	golden(t, "calendar_year", d.view())
+
+	// Zoom into the month, and move back to the game in progress
+	if _, err := d.keys("z", "left"); err != nil {
+		t.Fatal(err)
+	}
+	played, solved := s.monthSummary("2025-03")
+	d.send(monthSummaryMsg{month: "2025-03", counts: monthCounts{played, solved}})
+	golden(t, "calendar_month", d.view())
+
+	// A day that couldn't be loaded is marked until it's retried
+	sel := d.m.(calendarModel).sel
+	d.send(puzzleLoadedMsg{date: sel, err: errors.New("503 Service Unavailable")})
+	golden(t, "calendar_failed", d.view())
+	if _, err := d.keys("ctrl+r"); err != nil {
+		t.Fatal(err)
+	}
+	if cm := d.m.(calendarModel); len(cm.failed) != 0 || cm.status == "" {
+		t.Errorf("retrying didn't load the puzzle again: failed %v, status %q", cm.failed, cm.status)
+	}
+}
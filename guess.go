@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// errPuzzleLocked and errPuzzleFinished are why a game can't be played
+// without the TUI.
+var (
+	errPuzzleLocked   = errors.New("open in another brack")
+	errPuzzleFinished = errors.New("finished (use brack reset to play it again)")
+)
+
+// gameReport is a game's state, as given to scripts and other front
+// ends playing without the TUI.
+type gameReport struct {
+	broadcastState
+	GaveUp bool     `json:"gave_up"`
+	Clues  []string `json:"clues"` // the clues left to answer
+}
+
+// guessResult is the outcome of a guess made without the TUI, with the
+// game's state after it.
+type guessResult struct {
+	gameReport
+	Guess   string `json:"guess"`
+	Right   bool   `json:"right"`
+	Message string `json:"message,omitempty"` // e.g. why a right answer was turned away
+}
+
+func (m model) report() gameReport {
+	return gameReport{
+		broadcastState: m.snapshot(),
+		GaveUp:         m.gaveUp,
+		Clues:          m.activeClues(),
+	}
+}
+
+// openGame opens the game for a date to play without the TUI, as it
+// would be opened in the game, but refusing one that can't be played.
+func openGame(s *store, cfg config, d time.Time) (model, error) {
+	p, err := loadPuzzle(s, d)
+	if err != nil {
+		return model{}, err
+	}
+	m := newModel(p, s, cfg)
+	switch {
+	case m.lockedBy != "":
+		return m, fmt.Errorf("the puzzle for %s is %w (%s)", p.PuzzleDate, errPuzzleLocked, m.lockedBy)
+	case m.done || m.archived:
+		return m, fmt.Errorf("the puzzle for %s is %w", p.PuzzleDate, errPuzzleFinished)
+	}
+	// Answers are checked against every clue, as they are typed in
+	m.setSelectMode(false)
+	return m, nil
+}
+
+// guess enters an answer, as if it were typed into the game, and saves
+// the game. A wrong guess isn't an error. In hard mode, the returned
+// model's cooldown has to be waited out before the next guess.
+func (m model) guess(answer string) (model, guessResult, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return m, guessResult{}, errors.New("the guess is empty")
+	}
+
+	before := m.correct
+	m.toast = ""
+	m.chars += countLetters([]rune(answer))
+	m, _ = m.submit(answer)
+	m.save()
+	if m.saveErr != nil {
+		return m, guessResult{}, fmt.Errorf("couldn't save the game: %w", m.saveErr)
+	}
+	if m.done {
+		for _, cmd := range []tea.Cmd{m.notifyWebhook(), m.updateBadge()} {
+			if cmd != nil {
+				cmd()
+			}
+		}
+	}
+
+	return m, guessResult{
+		gameReport: m.report(),
+		Guess:      answer,
+		Right:      m.correct > before,
+		Message:    ansi.Strip(m.toast),
+	}, nil
+}
+
+// runGuess makes a guess at the puzzle for a date, writing the result
+// and the game's state to w, as text or JSON. In hard mode, a wrong
+// guess waits out the cooldown, so scripts don't get around it.
+func runGuess(w io.Writer, s *store, cfg config, d time.Time, answer string, asJSON bool) error {
+	m, err := openGame(s, cfg, d)
+	if err != nil {
+		return err
+	}
+	m, r, err := m.guess(answer)
+	if err != nil {
+		return err
+	}
+	time.Sleep(m.cooldownLeft())
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	switch {
+	case r.Message != "":
+		fmt.Fprintln(w, r.Message)
+	case r.Right:
+		fmt.Fprintf(w, "✓ %q is right\n", r.Guess)
+	default:
+		fmt.Fprintf(w, "✗ %q is wrong\n", r.Guess)
+	}
+	fmt.Fprintf(w, "\n%s\n\n", r.Puzzle)
+	fmt.Fprintf(w, "%s: %d/%d solved, %d incorrect\n", r.Date, r.Correct, r.Total, r.Incorrect)
+	if r.Done {
+		fmt.Fprintf(w, "Solved! Run brack %s to see your results.\n", r.Date)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// guestProfile is the profile for letting someone else play without
+// touching your games and stats. It starts empty each time it's
+// switched to.
+const guestProfile = "guest"
+
+// guestHost is the profile a guest is playing in place of, to switch
+// back to.
+type guestHost struct {
+	store *store
+	cfg   config
+}
+
+// profileSwitchedMsg is the result of switching to or from the guest
+// profile. host is set when switching to it.
+type profileSwitchedMsg struct {
+	store *store
+	cfg   config
+	host  *guestHost
+	err   error
+}
+
+// openGuestStore opens the guest profile's store, emptied, returning
+// it with the config to play as the guest.
+func openGuestStore(cfg config) (*store, config, error) {
+	cfg.Profile = guestProfile
+	path, err := storePath(cfg)
+	if err != nil {
+		return nil, cfg, err
+	}
+	if !noSave {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, cfg, err
+		}
+	}
+	s, err := openStore(path)
+	return s, cfg, err
+}
+
+// toggleGuest switches to the guest profile or, as the guest, back to
+// the profile it took over from.
+func (m model) toggleGuest() tea.Cmd {
+	if m.host != nil {
+		host := m.host
+		return func() tea.Msg {
+			return profileSwitchedMsg{store: host.store, cfg: host.cfg}
+		}
+	}
+	host := &guestHost{store: m.store, cfg: m.cfg}
+	return func() tea.Msg {
+		s, cfg, err := openGuestStore(host.cfg)
+		return profileSwitchedMsg{store: s, cfg: cfg, host: host, err: err}
+	}
+}
+
+// switchProfile saves the game, then starts the same puzzle again
+// with another profile's store.
+func (m model) switchProfile(msg profileSwitchedMsg) (model, tea.Cmd) {
+	m.save()
+	m.store, m.cfg = msg.store, msg.cfg
+	n, cmd := m.switchPuzzle(m.data, false)
+	n.cal = newCalendarPane(n.store, n.data.PuzzleDate)
+	n.host = msg.host
+	if n.host != nil {
+		n.toast = tr("Switched to a fresh guest profile, so your games and stats are left alone")
+	} else {
+		n.toast = tr("Switched back from the guest profile")
+	}
+	return n, cmd
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// enumeration gives the length of each word in an answer, crossword
+// style, e.g. "(7, 4)" for "bracket city".
+func enumeration(s string) string {
+	var ns []string
+	for _, w := range strings.Fields(s) {
+		ns = append(ns, fmt.Sprint(utf8.RuneCountInString(w)))
+	}
+	return "(" + strings.Join(ns, ", ") + ")"
+}
+
+// helperView is the letter helper panel: the shape of each active
+// clue's answer, and which of them the current guess fits. It never
+// shows any letters of the answers.
+func (m model) helperView() string {
+	qs := getActiveQuestions(m.data, m.state)
+	clues := make([]string, 0, len(qs))
+	for q := range qs {
+		clues = append(clues, q)
+	}
+	slices.Sort(clues)
+
+	guess := normalizeAnswer(m.txtin.Value(), m.cfg.IgnoreAccents)
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(tr("Letter helper")) + "\n")
+	for _, q := range clues {
+		shape := enumeration(normalizeAnswer(qs[q], m.cfg.IgnoreAccents))
+		line := activeStyle.Render("["+q+"]") + " " + shape
+		if guess != "" && enumeration(guess) == shape {
+			line += " ✓ " + tr("your guess fits")
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
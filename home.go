@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var _ tea.Model = homeModel{}
+
+// randomDays is how far back a random unplayed puzzle is picked from.
+const randomDays = 365
+
+// homeItem is an entry on the home screen.
+type homeItem struct {
+	label string
+	run   func(m homeModel) (homeModel, tea.Cmd)
+}
+
+// homeModel is the start screen, shown when brack is run without a date
+// once today's puzzle is finished: how today went, the streak, and
+// where to go next.
+type homeModel struct {
+	store  *store
+	cfg    config
+	items  []homeItem
+	cursor int
+	status string
+	tickID int
+}
+
+func newHomeModel(s *store, cfg config) homeModel {
+	m := homeModel{store: s, cfg: cfg}
+	m.items = m.menu()
+	return m
+}
+
+// menu lists what can be done from the home screen, which depends on
+// what's been played.
+func (m homeModel) menu() []homeItem {
+	var items []homeItem
+	if date, unfinished, ok := suggestPuzzle(m.store, today()); ok {
+		label := tr("Play the puzzle from %s", date)
+		if unfinished {
+			label = tr("Resume the puzzle from %s", date)
+		}
+		items = append(items, homeItem{label, func(m homeModel) (homeModel, tea.Cmd) { return m.play(date) }})
+	}
+	items = append(items,
+		homeItem{tr("Today's results"), func(m homeModel) (homeModel, tea.Cmd) { return m.play(today()) }},
+		homeItem{tr("Calendar"), func(m homeModel) (homeModel, tea.Cmd) {
+			cm := newCalendarModel(m.store, time.Now())
+			cm.cfg, cm.stacked = m.cfg, true
+			return m, push(cm)
+		}},
+		homeItem{tr("Stats"), func(m homeModel) (homeModel, tea.Cmd) { return m, push(newStatsModel(m.store)) }},
+		homeItem{tr("Trends"), func(m homeModel) (homeModel, tea.Cmd) { return m, push(newTrendsModel(m.store, time.Now())) }},
+	)
+	if unplayedPuzzles(m.store, time.Now()) != nil {
+		items = append(items, homeItem{tr("A random unplayed puzzle"), homeModel.playRandom})
+	}
+	items = append(items, homeItem{tr("Let a guest play today's puzzle"), homeModel.playAsGuest})
+	return append(items, homeItem{tr("Quit"), func(m homeModel) (homeModel, tea.Cmd) { return m, tea.Quit }})
+}
+
+// play loads the puzzle for a date, and opens the game over the home
+// screen.
+func (m homeModel) play(date string) (homeModel, tea.Cmd) {
+	d, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.status = tr("Loading the puzzle for %s...", date)
+	s, cfg := m.store, m.cfg
+	return m, func() tea.Msg {
+		p, err := loadPuzzle(s, d)
+		if err != nil {
+			return puzzleLoadedMsg{err: err}
+		}
+		return pushMsg{view: newSession(newModel(p, s, cfg))}
+	}
+}
+
+// playAsGuest opens today's puzzle with a fresh guest profile, so
+// someone else can have a go.
+func (m homeModel) playAsGuest() (homeModel, tea.Cmd) {
+	m.status = tr("Loading the puzzle for %s...", today())
+	host := &guestHost{store: m.store, cfg: m.cfg}
+	return m, func() tea.Msg {
+		p, err := loadPuzzle(host.store, time.Now())
+		if err != nil {
+			return puzzleLoadedMsg{err: err}
+		}
+		s, cfg, err := openGuestStore(host.cfg)
+		if err != nil {
+			return puzzleLoadedMsg{err: err}
+		}
+		g := newModel(p, s, cfg)
+		g.host = host
+		return pushMsg{view: newSession(g)}
+	}
+}
+
+// playRandom plays a puzzle picked at random from the last year's
+// unplayed ones.
+func (m homeModel) playRandom() (homeModel, tea.Cmd) {
+	dates := unplayedPuzzles(m.store, time.Now())
+	if len(dates) == 0 {
+		return m, nil
+	}
+	return m.play(dates[rng.IntN(len(dates))])
+}
+
+// unplayedPuzzles returns the dates in the last year, before today,
+// with no game played.
+func unplayedPuzzles(s *store, now time.Time) []string {
+	var dates []string
+	for i := 1; i <= randomDays; i++ {
+		d := now.AddDate(0, 0, -i).Format(time.DateOnly)
+		if _, played := s.game(d); !played {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// homeTickMsg redraws the countdown to the next puzzle. Like the
+// game's ticks, ones from an earlier tick loop are ignored.
+type homeTickMsg struct {
+	id int
+}
+
+func (m homeModel) tick() tea.Cmd {
+	id := m.tickID
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return homeTickMsg{id: id} })
+}
+
+func (m homeModel) Init() tea.Cmd {
+	return m.tick()
+}
+
+func (m homeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case puzzleLoadedMsg:
+		m.status = tr("Couldn't load the puzzle: %v", msg.err)
+
+	case homeTickMsg:
+		if msg.id != m.tickID {
+			return m, nil
+		}
+		return m, m.tick()
+
+	case shownMsg:
+		// Games may have been played in the meantime, and the tick
+		// loop stopped while another view was on top
+		m.status = ""
+		m.items = m.menu()
+		m.cursor = min(m.cursor, len(m.items)-1)
+		m.tickID++
+		return m, m.tick()
+
+	case tea.KeyMsg:
+		m.status = ""
+		switch {
+		case key.Matches(msg, keys.Quit), key.Matches(msg, keys.Close):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Up):
+			m.cursor = (m.cursor + len(m.items) - 1) % len(m.items)
+		case key.Matches(msg, keys.Down):
+			m.cursor = (m.cursor + 1) % len(m.items)
+		case key.Matches(msg, keys.Submit):
+			return m.items[m.cursor].run(m)
+		}
+	}
+	return m, nil
+}
+
+func (m homeModel) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("[ Bracket City ]") + "\n\n")
+
+	if gs, ok := m.store.game(today()); ok {
+		switch {
+		case gs.GaveUp:
+			b.WriteString(tr("Today's puzzle: gave up") + "\n")
+		case gs.Done:
+			b.WriteString(tr("Today's puzzle: solved in %s, with %d incorrect", formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second), gs.Incorrect) + "\n")
+		default:
+			b.WriteString(tr("Today's puzzle: in progress") + "\n")
+		}
+	}
+	b.WriteString(tr("🔥 %d-day streak", currentStreak(m.store, time.Now())) + "\n")
+	now := time.Now()
+	b.WriteString(noticeStyle.Render(tr("⏳ The next puzzle is out in %s", formatElapsed(nextPuzzleAt(now).Sub(now)))) + "\n\n")
+
+	for i, item := range m.items {
+		line := fmt.Sprintf("  %s", item.label)
+		if i == m.cursor {
+			line = selectedStyle.Render("> " + item.label)
+		}
+		b.WriteString(line + "\n")
+	}
+	if m.status != "" {
+		b.WriteString("\n" + m.status + "\n")
+	}
+
+	choose := keys.Submit
+	choose.SetHelp("enter", "choose")
+	b.WriteString("\n" + noticeStyle.Render(helpLine(keys.Up, keys.Down, choose, keys.Close)))
+	return lipgloss.NewStyle().Padding(0, 1).Render(b.String())
+}
+
+// showHome runs the home screen.
+func showHome(s *store, cfg config) error {
+	// Detect the background color before the home screen starts
+	// reading input
+	setTheme(cfg.Theme)
+
+	top, err := runViews(newHomeModel(s, cfg), whatsNew(s)...)
+	if err != nil {
+		return err
+	}
+	if cfg.RestoreSession {
+		return saveLastView(s, top)
+	}
+	return nil
+}
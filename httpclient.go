@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// userAgent identifies brack to the servers it talks to.
+const userAgent = "brack/" + version + " (+https://github.com/a-poor/brack)"
+
+// httpClient makes brack's HTTP requests, with the proxy and TLS
+// settings from setupHTTP. Requests time out, so a stalled server can't
+// hang the game, doctor or the update check.
+var httpClient = &http.Client{
+	Transport: userAgentTransport{http.DefaultTransport},
+	Timeout:   30 * time.Second,
+}
+
+// userAgentTransport sets brack's User-Agent on each request.
+type userAgentTransport struct {
+	http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("User-Agent", userAgent)
+	return t.RoundTripper.RoundTrip(r)
+}
+
+// How many times to retry a request that's rate limited, and the
+// longest brack will wait to do so.
+const (
+	maxRetries   = 2
+	maxRetryWait = 30 * time.Second
+)
+
+// getPolitely GETs a URL, waiting as long as the server asks (in
+// Retry-After) and trying again if it's rate limited.
+func getPolitely(url string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := httpClient.Get(url)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		resp.Body.Close()
+		wait := retryAfter(resp.Header.Get("Retry-After"))
+		if attempt >= maxRetries || wait > maxRetryWait {
+			return nil, fmt.Errorf("rate limited by %s, try again in %s", resp.Request.URL.Host, wait.Round(time.Second))
+		}
+		slog.Warn("rate limited, waiting to retry", "url", url, "wait", wait)
+		time.Sleep(wait)
+	}
+}
+
+// retryAfter parses a Retry-After header, either a number of seconds
+// or a date, defaulting to a few seconds.
+func retryAfter(h string) time.Duration {
+	if secs, err := strconv.Atoi(h); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 5 * time.Second
+}
+
+// setupHTTP configures the HTTP clients: proxies are taken from
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY, certificates from caBundle (a
+// PEM file) are trusted as well as the system's, and with insecure,
+// certificates aren't checked at all.
+func setupHTTP(caBundle string, insecure bool) error {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caBundle != "" {
+		pem, err := os.ReadFile(expandHome(caBundle))
+		if err != nil {
+			return fmt.Errorf("failed to read the CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in the CA bundle %s", caBundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if insecure {
+		slog.Warn("not verifying TLS certificates")
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	t.TLSClientConfig = tlsConfig
+	httpClient.Transport = userAgentTransport{t}
+	webhookClient.Transport = userAgentTransport{t}
+	return nil
+}
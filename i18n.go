@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// locale is the language used for the UI, e.g. "en" or "es".
+var locale = "en"
+
+// catalogs holds the UI translations for each supported locale,
+// keyed by the English text (which doubles as the English catalog).
+// Puzzle text itself is only published in English.
+var catalogs = map[string]map[string]string{
+	"es": {
+		// Game
+		"🎉 You win! 🎉":                   "🎉 ¡Ganaste! 🎉",
+		"🏅 New personal best!":           "🏅 ¡Nuevo récord personal!",
+		"Copied the %s to the clipboard": "Se copió %s al portapapeles",
+		"URL":                            "la URL",
+		"share text":                     "el texto para compartir",
+		"⚠️ Couldn't save your progress: %v (%s to retry, %s to dismiss)":           "⚠️ No se pudo guardar tu progreso: %v (%s para reintentar, %s para descartar)",
+		"🏳️ You gave up (%s to see what you missed)":                                "🏳️ Te rendiste (%s para ver lo que te faltó)",
+		"Every answer was solved.":                                                  "Todas las respuestas fueron resueltas.",
+		"No clue timings were recorded for this game.":                              "No se registraron tiempos por pista en esta partida.",
+		"⏱️ How long each clue took, in the order solved:":                          "⏱️ Cuánto tardaste en cada pista, en el orden resuelto:",
+		"%s: revealed · %s: left unsolved":                                          "%s: revelado · %s: sin resolver",
+		"highlighted":                                                               "resaltado",
+		"struck through":                                                            "tachado",
+		"Couldn't send the webhook: %v":                                             "No se pudo enviar el webhook: %v",
+		"Couldn't open the browser: %v":                                             "No se pudo abrir el navegador: %v",
+		"(solution hidden in streamer mode)":                                        "(solución oculta en modo streamer)",
+		"(hidden in streamer mode)":                                                 "(oculto en modo streamer)",
+		"🔒 Streamer mode is on (%s to turn it off)":                                 "🔒 El modo streamer está activado (%s para desactivarlo)",
+		"📡 Broadcasting at %s":                                                      "📡 Transmitiendo en %s",
+		"👤 Playing as a guest (:guest to switch back)":                              "👤 Jugando como invitado (:guest para volver)",
+		"Switched to a fresh guest profile, so your games and stats are left alone": "Se cambió a un perfil de invitado nuevo, así que tus partidas y estadísticas no se tocan",
+		"Switched back from the guest profile":                                      "Se volvió del perfil de invitado",
+		"Couldn't switch profiles: %v":                                              "No se pudo cambiar de perfil: %v",
+		"Let a guest play today's puzzle":                                           "Dejar que un invitado juegue el puzle de hoy",
+		"switch to a throwaway guest profile, or back":                              "cambiar a un perfil de invitado desechable, o volver",
+		"🔐 This puzzle is open in another brack (%s), so it's read-only here (%s to check again)": "🔐 Este puzle está abierto en otro brack (%s), así que aquí es de solo lectura (%s para volver a comprobar)",
+		"The puzzle is still open in another brack, this is its progress so far":                  "El puzle sigue abierto en otro brack, este es su progreso hasta ahora",
+		"The other brack closed the puzzle, so it's yours to play":                                "El otro brack cerró el puzle, así que ya puedes jugarlo",
+		"This puzzle was played elsewhere (%d solved there, %d here). Load that progress?":        "Este puzle se jugó en otro sitio (%d resueltas allí, %d aquí). ¿Cargar ese progreso?",
+		"Load it":                                   "Cargarlo",
+		"Keep mine":                                 "Mantener el mío",
+		"👥 Who solved what:":                        "👥 Quién resolvió qué:",
+		"The host is on another puzzle":             "El anfitrión está en otro puzle",
+		"The puzzle is finished":                    "El puzle está terminado",
+		"The puzzle is read-only for the host":      "El puzle es de solo lectura para el anfitrión",
+		"The host has paused the game":              "El anfitrión ha pausado la partida",
+		"Cooling down after a wrong guess, wait %s": "Esperando tras un intento fallido, espera %s",
+		"👥 %s got %q":                               "👥 %s acertó %q",
+		"👥 %s guessed %q, which is wrong":           "👥 %s probó %q, que es incorrecto",
+		"👥 %s joined":                               "👥 %s se unió",
+		"👥 %s left":                                 "👥 %s se fue",
+		"👥 Hosting a team game, join with brack join %s (%d joined)": "👥 Organizando una partida en equipo, únete con brack join %s (%d unidos)",
+		"✅ You got %q":                   "✅ Acertaste %q",
+		"❌ %q is wrong":                  "❌ %q es incorrecto",
+		"The host ended the team game":   "El anfitrión terminó la partida en equipo",
+		"Couldn't send the guess: %v":    "No se pudo enviar el intento: %v",
+		"Waiting for the host...":        "Esperando al anfitrión...",
+		"🏳️ The host gave up":            "🏳️ El anfitrión se rindió",
+		"leave":                          "salir",
+		"👥 Playing in a team game as %s": "👥 Jugando en equipo como %s",
+		"%s played meanwhile, so your last guess wasn't saved":                     "%s jugó mientras tanto, así que tu último intento no se guardó",
+		"🏠 %s played: %d/%d solved":                                                "🏠 %s jugó: %d/%d resueltas",
+		"This is a shared game, run brack to play other puzzles":                   "Esta es una partida compartida, ejecuta brack para jugar otros puzles",
+		"🏠 Playing the shared game in %s, last saved by %s":                        "🏠 Jugando la partida compartida en %s, guardada por última vez por %s",
+		"🏠 Playing the shared game in %s":                                          "🏠 Jugando la partida compartida en %s",
+		"You haven't solved this puzzle. Show its answers anyway?":                 "No has resuelto este puzle. ¿Mostrar sus respuestas de todos modos?",
+		"Score: %d/100 (%s penalties: hint -%d, reveal -%d, wrong guess -%d)":      "Puntuación: %d/100 (penalizaciones %s: pista -%d, revelar -%d, intento fallido -%d)",
+		"New games will be scored with the %s penalties":                           "Las partidas nuevas se puntuarán con las penalizaciones %s",
+		"🧪 Nothing is being saved (--no-save)":                                     "🧪 No se está guardando nada (--no-save)",
+		"brack %s is available, run `brack upgrade` to install it (%s to dismiss)": "brack %s está disponible, ejecuta `brack upgrade` para instalarlo (%s para descartar)",
+
+		"%d clues · %d words · ~%d min read":       "%d pistas · %d palabras · ~%d min de lectura",
+		"difficulty %s":                            "dificultad %s",
+		"usually ~%.1f wrong on puzzles like this": "normalmente ~%.1f errores en puzles así",
+
+		"⏸️ Paused":    "⏸️ En pausa",
+		"%s to resume": "%s para continuar",
+
+		// Key bindings
+		"submit answer":           "enviar respuesta",
+		"dismiss notice":          "descartar aviso",
+		"toggle streamer mode":    "activar/desactivar modo streamer",
+		"rules & info":            "reglas e info",
+		"quit":                    "salir",
+		"play again":              "jugar de nuevo",
+		"open in browser":         "abrir en el navegador",
+		"copy URL":                "copiar URL",
+		"copy share text":         "copiar texto para compartir",
+		"back":                    "volver",
+		"pause":                   "pausar",
+		"retry":                   "reintentar",
+		"give up":                 "rendirse",
+		"letter helper":           "ayuda de letras",
+		"look up a word":          "buscar una palabra",
+		"look up":                 "buscar",
+		"previous":                "anterior",
+		"next":                    "siguiente",
+		"zoom":                    "zoom",
+		"play":                    "jugar",
+		"command palette":         "paleta de comandos",
+		"today's puzzle":          "el puzle de hoy",
+		"today's puzzle (solved)": "el puzle de hoy (resuelto)",
+		"Yes":                     "Sí",
+		"No":                      "No",
+		"Give up on this puzzle?": "¿Rendirse con este puzle?",
+		"Start this puzzle over? This attempt will be archived.": "¿Empezar este puzle de nuevo? Este intento se archivará.",
+		"You have unsaved progress on %s.":                       "Tienes progreso sin guardar en %s.",
+		"Save":                                                   "Guardar",
+		"Discard":                                                "Descartar",
+		"Cancel":                                                 "Cancelar",
+		"Counting up your games...":                              "Contando tus partidas...",
+		"Trends":                                                 "Tendencias",
+		"Solve a puzzle or two to see your trends.": "Resuelve un puzle o dos para ver tus tendencias.",
+		"%d-day average of %s:":                     "Media de %d días de %s:",
+		"Nothing to chart for %s in this window.":   "No hay nada que mostrar de %s en este periodo.",
+		"Now: %s":                   "Ahora: %s",
+		"solve time":                "tiempo de resolución",
+		"keystrokes":                "pulsaciones",
+		"3 months":                  "3 meses",
+		"6 months":                  "6 meses",
+		"1 year":                    "1 año",
+		"all time":                  "todo",
+		"next metric":               "siguiente métrica",
+		"time window":               "periodo",
+		"play the suggested puzzle": "jugar el puzle sugerido",
+		"👉 You haven't finished the puzzle from %s — press %s to pick it back up": "👉 No has terminado el puzle del %s — pulsa %s para retomarlo",
+		"👉 Today's puzzle is unplayed — press %s to play it":                      "👉 No has jugado el puzle de hoy — pulsa %s para jugarlo",
+		"👉 Yesterday's puzzle is unplayed — press %s to play it":                  "👉 No has jugado el puzle de ayer — pulsa %s para jugarlo",
+		"👉 The puzzle from %s is unplayed — press %s to play it":                  "👉 No has jugado el puzle del %s — pulsa %s para jugarlo",
+		"Warmed up! Press %s to play today's puzzle.":                             "¡Ya has calentado! Pulsa %s para jugar el puzle de hoy.",
+		"Warm up: %s.": "Calentamiento: %s.",
+		"Review":       "Repaso",
+		"Nothing to review today. Solve some puzzles, or come back tomorrow.": "Nada que repasar hoy. Resuelve algunos puzles, o vuelve mañana.",
+		"answer (blank if you don't know)":                                    "responder (en blanco si no lo sabes)",
+		"✅ Correct!":                                                          "✅ ¡Correcto!",
+		"❌ The answer was %s":                                                 "❌ La respuesta era %s",
+		"Next review in %d days":                                              "Próximo repaso en %d días",
+		"Next review tomorrow":                                                "Próximo repaso mañana",
+		"Reviewed":                                                            "Repasadas",
+		"Recalled":                                                            "Recordadas",
+		"Cards learned":                                                       "Tarjetas aprendidas",
+		"Due tomorrow":                                                        "Para mañana",
+		"Recall rate":                                                         "Tasa de acierto",
+		"⏳ Hard mode: wait %ds to guess again":                                "⏳ Modo difícil: espera %ds para volver a intentarlo",
+		"🧩 %d of %d words right for %s":                                       "🧩 %d de %d palabras correctas para %s",
+		"🧩 Partly right for %s: %s":                                           "🧩 Casi para %s: %s",
+		"🎓 Expert mode: solve the innermost clues first":                      "🎓 Modo experto: resuelve primero las pistas más internas",
+		"🎓 expert":                                                            "🎓 experto",
+		"Expert mode":                                                         "Modo experto",
+		"👻 ghost %d/%d":                                                       "👻 fantasma %d/%d",
+		"%s ahead":                                                            "%s por delante",
+		"%s behind":                                                           "%s por detrás",
+		"There's no earlier attempt to race":                                  "No hay ningún intento anterior contra el que competir",
+		"⏳ The next puzzle is out in %s":                                      "⏳ El próximo puzle sale en %s",
+		"Play the puzzle from %s":                                             "Jugar el puzle del %s",
+		"Resume the puzzle from %s":                                           "Continuar el puzle del %s",
+		"Today's results":                                                     "Resultados de hoy",
+		"Calendar":                                                            "Calendario",
+		"couldn't load the puzzle: %v":                                        "no se pudo cargar el puzle: %v",
+		"couldn't load":                                                       "no se pudo cargar",
+		"A random unplayed puzzle":                                            "Un puzle sin jugar al azar",
+		"Quit":                                                                "Salir",
+		"Today's puzzle: gave up":                                             "El puzle de hoy: abandonado",
+		"Today's puzzle: solved in %s, with %d incorrect":                     "El puzle de hoy: resuelto en %s, con %d fallos",
+		"Today's puzzle: in progress":                                         "El puzle de hoy: en curso",
+		"🔥 %d-day streak":                                                     "🔥 Racha de %d días",
+		"choose":                                                              "elegir",
+		"edit note":                                                           "editar nota",
+		"Couldn't save the note: %v":                                          "No se pudo guardar la nota: %v",
+		"📝 Note (enter to save, esc to cancel):":                              "📝 Nota (enter para guardar, esc para cancelar):",
+		"%d attempts":                                                         "%d intentos",
+		"fewest incorrect: %d":                                                "menos fallos: %d",
+		"fastest: %s":                                                         "más rápido: %s",
+		"📖 Your last finished attempt, read-only (%s to play again)": "📖 Tu último intento terminado, solo lectura (%s para volver a jugar)",
+		"Type a clue's number": "Escribe el número de una pista",
+		"There's no clue %d":   "No hay ninguna pista %d",
+		"switch tab":           "cambiar de pestaña",
+		"switch pane":          "cambiar de panel",
+		"go to...":             "ir a...",
+		"close":                "cerrar",
+		"move":                 "mover",
+		"go":                   "ir",
+		"hide legend":          "ocultar leyenda",
+		"show legend":          "mostrar leyenda",
+		"up":                   "arriba",
+		"down":                 "abajo",
+		"compare to solution":  "comparar con la solución",
+		"clue timings":         "tiempos por pista",
+
+		"⚠️ Couldn't save your progress: %v":       "⚠️ No se pudo guardar tu progreso: %v",
+		"💡 Stuck? Try the shortest clue: %s":       "💡 ¿Atascado? Prueba la pista más corta: %s",
+		"Letter helper":                            "Ayuda de letras",
+		"your guess fits":                          "tu respuesta encaja",
+		"🛟 assisted":                               "🛟 con ayuda",
+		"Look up a word":                           "Buscar una palabra",
+		"Looking up %s...":                         "Buscando %s...",
+		"Couldn't look up %s: %v":                  "No se pudo buscar %s: %v",
+		"No definition found for %s":               "No se encontró ninguna definición de %s",
+		"solved":                                   "resuelto",
+		"gave up":                                  "abandonado",
+		"in progress":                              "en curso",
+		"not played":                               "sin jugar",
+		"%s — %d/%d played, %d completed":          "%s — %d/%d jugados, %d completados",
+		"1–3 incorrect":                            "1–3 fallos",
+		"4+ incorrect":                             "4+ fallos",
+		"Stats":                                    "Estadísticas",
+		"Stats aren't kept without a database.":    "Sin base de datos no se guardan estadísticas.",
+		"Games played":                             "Partidas jugadas",
+		"Puzzles solved":                           "Puzles resueltos",
+		"Current streak (days)":                    "Racha actual (días)",
+		"Incorrect per solve":                      "Fallos por puzle",
+		"Average solve time":                       "Tiempo medio",
+		"Loading the puzzle for %s...":             "Cargando el puzle del %s...",
+		"Couldn't load the puzzle: %v":             "No se pudo cargar el puzle: %v",
+		"Invalid date %q":                          "Fecha no válida %q",
+		"Unknown command %q":                       "Comando desconocido %q",
+		"Unknown theme %q, expected dark or light": "Tema desconocido %q, se esperaba dark o light",
+		"command, e.g. date 2024-03-01":            "comando, p. ej. date 2024-03-01",
+		"play the puzzle for another date":         "jugar el puzle de otra fecha",
+		"set the color theme":                      "elegir el tema de color",
+		"show your stats":                          "ver tus estadísticas",
+		"Play today's puzzle":                      "Jugar el puzle de hoy",
+		"Play %s":                                  "Jugar %s",
+		"Go to...":                                 "Ir a...",
+		"Dark theme":                               "Tema oscuro",
+		"Light theme":                              "Tema claro",
+		"open the puzzle for another date in a new tab":           "abrir el puzle de otra fecha en una pestaña nueva",
+		"toggle answering clues by number":                        "activar o desactivar responder pistas por número",
+		"toggle racing your best attempt":                         "activar o desactivar la carrera contra tu mejor intento",
+		"Hints per solve":                                         "Ayudas por puzle",
+		"📝 %s":                                                    "📝 %s",
+		"choose how new games are scored":                         "elegir cómo se puntúan las partidas nuevas",
+		"chart your solve times, keystrokes and errors over time": "ver la evolución de tus tiempos, pulsaciones y fallos",
+		// Rules and about screen
+		"How to play":      "Cómo jugar",
+		"[bracketed clue]": "[pista entre corchetes]",
+		"Each %s hides an answer. Type the answer to any highlighted clue and press enter. Solved answers replace their brackets, which can reveal the clues around them, so work from the inside out. The puzzle is done when every bracket is solved.": "Cada %s esconde una respuesta. Escribe la respuesta de cualquier pista resaltada y pulsa enter. Las respuestas correctas reemplazan sus corchetes, lo que puede revelar las pistas que las rodean, así que trabaja de dentro hacia fuera. El puzle termina cuando todos los corchetes están resueltos.",
+		"Scoring":           "Puntuación",
+		"answers found":     "respuestas encontradas",
+		"incorrect guesses": "intentos incorrectos",
+		"letters typed":     "letras escritas",
+		"Fewer incorrect guesses and keystrokes is better.": "Cuantos menos intentos incorrectos y pulsaciones, mejor.",
+		"Controls":               "Controles",
+		"On the results screen:": "En la pantalla de resultados:",
+		"About":                  "Acerca de",
+		"Bracket City is published by The Atlantic: %s": "Bracket City es publicado por The Atlantic: %s",
+		"Config:":    "Configuración:",
+		"Database:":  "Base de datos:",
+		"what's new": "novedades",
+
+		// What's new screen
+		"What's new in brack v%s": "Novedades de brack v%s",
+		"New and changed keys:":   "Teclas nuevas y cambiadas:",
+		"A home screen once today's puzzle is done, with a countdown to the next one": "Una pantalla de inicio al terminar el puzle de hoy, con una cuenta atrás hasta el siguiente",
+		"A calendar with a year heatmap, shown beside the game on wide terminals":     "Un calendario con un mapa de calor del año, junto al juego en terminales anchas",
+		"Open puzzles in tabs, and pick up where the last session left off":           "Abre puzles en pestañas y continúa donde lo dejaste en la última sesión",
+		"Hard mode, expert mode, select mode and a ghost of your fastest attempt":     "Modo difícil, modo experto, modo de selección y un fantasma de tu intento más rápido",
+		"brack warmup and brack review, to practice previously solved clues":          "brack warmup y brack review, para practicar pistas ya resueltas",
+		"Notes and attempt history on the results screen":                             "Notas e historial de intentos en la pantalla de resultados",
+	},
+}
+
+// tr translates a UI string into the current locale, formatting it
+// with any args. Strings without a translation are used as-is.
+func tr(s string, args ...any) string {
+	if t, ok := catalogs[locale][s]; ok {
+		s = t
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(s, args...)
+	}
+	return s
+}
+
+// checkLocale returns an error for a locale without a translation.
+func checkLocale(l string) error {
+	if _, ok := catalogs[strings.ToLower(l)]; !ok && l != "" && !strings.EqualFold(l, "en") {
+		return fmt.Errorf("unsupported locale %q (expected en or es)", l)
+	}
+	return nil
+}
+
+// setLocale picks the UI locale: from the config if it's set there,
+// otherwise from the environment (LC_ALL, LC_MESSAGES, LANG). Unknown
+// locales fall back to English.
+func setLocale(cfgLocale string) {
+	l := cfgLocale
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if l != "" {
+			break
+		}
+		l = os.Getenv(env)
+	}
+
+	// "es_MX.UTF-8" -> "es"
+	l, _, _ = strings.Cut(l, ".")
+	l, _, _ = strings.Cut(l, "_")
+	l = strings.ToLower(l)
+	if _, ok := catalogs[l]; !ok {
+		l = "en"
+	}
+	locale = l
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// uiStrings finds the strings the UI passes through tr: literals given
+// to it directly, and those it's given by way of key help, stats rows,
+// calendar swatches, the palette's commands and so on.
+func uiStrings(t *testing.T) []string {
+	t.Helper()
+	var out []string
+	lit := func(e ast.Expr) {
+		if bl, ok := e.(*ast.BasicLit); ok && bl.Kind == token.STRING {
+			if s, err := strconv.Unquote(bl.Value); err == nil {
+				out = append(out, s)
+			}
+		}
+	}
+
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				switch fun := n.Fun.(type) {
+				case *ast.Ident:
+					switch fun.Name {
+					case "tr", "row", "copyToClipboard":
+						if len(n.Args) > 0 {
+							lit(n.Args[0])
+						}
+					}
+				case *ast.SelectorExpr:
+					if fun.Sel.Name == "SetHelp" && len(n.Args) == 2 {
+						lit(n.Args[1])
+					}
+				}
+			case *ast.CompositeLit:
+				// The calendar's legend, []swatch{{style, desc}, ...}
+				if at, ok := n.Type.(*ast.ArrayType); ok {
+					if id, ok := at.Elt.(*ast.Ident); ok && id.Name == "swatch" {
+						for _, e := range n.Elts {
+							if cl, ok := e.(*ast.CompositeLit); ok && len(cl.Elts) == 2 {
+								lit(cl.Elts[1])
+							}
+						}
+					}
+				}
+				if id, ok := n.Type.(*ast.Ident); ok && id.Name == "swatch" && len(n.Elts) == 2 {
+					lit(n.Elts[1])
+				}
+			}
+			return true
+		})
+	}
+
+	v := reflect.ValueOf(keys)
+	for i := range v.NumField() {
+		out = append(out, v.Field(i).Interface().(key.Binding).Help().Desc)
+	}
+	for _, c := range paletteCommands {
+		out = append(out, c.desc)
+	}
+	for _, mt := range trendMetrics {
+		out = append(out, mt.name)
+	}
+	for _, w := range trendWindows {
+		out = append(out, w.name)
+	}
+	for _, c := range changelog {
+		out = append(out, c.features...)
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Every string in the UI should be in each catalog, so the screens
+// aren't a mix of languages.
+func TestCatalogsComplete(t *testing.T) {
+	for _, s := range uiStrings(t) {
+		if !strings.ContainsFunc(s, unicode.IsLetter) {
+			continue
+		}
+		for l, catalog := range catalogs {
+			if _, ok := catalog[s]; !ok {
+				t.Errorf("%s: no translation of %q", l, s)
+			}
+		}
+	}
+}
+
+// Translations have to keep the verbs of the English, in order, or
+// they'll be formatted wrong.
+func TestCatalogsVerbs(t *testing.T) {
+	verbs := func(s string) []string {
+		var vs []string
+		for i := 0; i < len(s)-1; i++ {
+			if s[i] == '%' {
+				vs = append(vs, s[i:i+2])
+				i++
+			}
+		}
+		return vs
+	}
+	for l, catalog := range catalogs {
+		for en, tr := range catalog {
+			if !slices.Equal(verbs(en), verbs(tr)) {
+				t.Errorf("%s: %q has verbs %v, but %q has %v", l, en, verbs(en), tr, verbs(tr))
+			}
+		}
+	}
+}
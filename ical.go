@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// icalEscape escapes text for an iCalendar property value.
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icalLine writes a content line, folded at 75 octets as RFC 5545
+// requires (without splitting a UTF-8 sequence).
+func icalLine(w io.Writer, line string) {
+	for len(line) > 75 {
+		i := 75
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+		fmt.Fprintf(w, "%s\r\n", line[:i])
+		line = " " + line[i:]
+	}
+	fmt.Fprintf(w, "%s\r\n", line)
+}
+
+// writeICal writes an iCalendar file with an all-day event for each
+// solved puzzle. With scrub, the events don't link to the solved
+// puzzles, whose answers they show.
+func writeICal(w io.Writer, s *store, now time.Time, scrub bool) error {
+	var games []gamestate
+	for _, gs := range s.allGames() {
+		if gs.Done && !gs.GaveUp {
+			games = append(games, gs)
+		}
+	}
+	slices.SortFunc(games, func(a, b gamestate) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	icalLine(w, "BEGIN:VCALENDAR")
+	icalLine(w, "VERSION:2.0")
+	icalLine(w, "PRODID:-//brack//brack "+version+"//EN")
+	icalLine(w, "X-WR-CALNAME:Bracket City")
+	seen := make(map[string]int)
+	for _, gs := range games {
+		day, err := time.Parse(time.DateOnly, gs.Date)
+		if err != nil {
+			continue // e.g. the demo puzzle
+		}
+
+		summary := fmt.Sprintf("Bracket City solved (%d incorrect", gs.Incorrect)
+		if gs.ElapsedSeconds > 0 {
+			summary += fmt.Sprintf(", %s, #%d fastest", formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second), solveRank(s, gs))
+		}
+		summary += ")"
+
+		// Replays of the same puzzle each get their own event
+		seen[gs.Date]++
+		icalLine(w, "BEGIN:VEVENT")
+		icalLine(w, fmt.Sprintf("UID:%s-%d@brack", gs.Date, seen[gs.Date]))
+		icalLine(w, "DTSTAMP:"+now.UTC().Format("20060102T150405Z"))
+		icalLine(w, "DTSTART;VALUE=DATE:"+day.Format("20060102"))
+		icalLine(w, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+		icalLine(w, "SUMMARY:"+icalEscape(summary))
+		if p, ok := s.puzzle(gs.Date); ok && p.CompletionURL != "" && !scrub {
+			icalLine(w, "URL:"+p.CompletionURL)
+		}
+		icalLine(w, "TRANSP:TRANSPARENT")
+		icalLine(w, "END:VEVENT")
+	}
+	icalLine(w, "END:VCALENDAR")
+	return nil
+}
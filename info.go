@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var _ tea.Model = infoModel{}
+
+// infoModel shows the rules, controls and about screen, over the game.
+type infoModel struct {
+	store *store
+	w     int
+}
+
+func (m infoModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m infoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w = msg.Width
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Info, keys.Close):
+			return m, popView
+		case key.Matches(msg, keys.WhatsNew):
+			return m, push(changelogModel{releases: changelog, w: m.w})
+		}
+	}
+	return m, nil
+}
+
+func (m infoModel) View() string {
+	var b strings.Builder
+	para := lipgloss.NewStyle().Width(min(m.w, 80))
+
+	b.WriteString(headerStyle.Render(tr("How to play")) + "\n")
+	b.WriteString(para.Render(tr(
+		"Each %s hides an answer. Type the answer to any highlighted clue and press enter. Solved answers replace their brackets, which can reveal the clues around them, so work from the inside out. The puzzle is done when every bracket is solved.",
+		activeStyle.Render(tr("[bracketed clue]")),
+	)) + "\n\n")
+
+	b.WriteString(headerStyle.Render(tr("Scoring")) + "\n")
+	b.WriteString("✅ " + tr("answers found") + "   ❌ " + tr("incorrect guesses") + "   ⌨️ " + tr("letters typed") + "\n")
+	b.WriteString(para.Render(tr("Fewer incorrect guesses and keystrokes is better.")) + "\n\n")
+
+	b.WriteString(headerStyle.Render(tr("Controls")) + "\n")
+	writeBindings(&b, keys.Submit, keys.Info, keys.Pause, keys.Helper, keys.GiveUp, keys.Palette, keys.Launcher, keys.Focus, keys.SwitchTab, keys.Today, keys.Streamer, keys.Dismiss, keys.Quit)
+	b.WriteString(tr("On the results screen:") + "\n")
+	writeBindings(&b, keys.PlayAgain, keys.Next, keys.Diff, keys.Timing, keys.Lookup, keys.Open, keys.CopyURL, keys.Share, keys.Close)
+	b.WriteString("\n")
+
+	b.WriteString(headerStyle.Render(tr("About")) + "\n")
+	b.WriteString("brack v" + version + " · https://github.com/a-poor/brack\n")
+	b.WriteString(tr("Bracket City is published by The Atlantic: %s", "https://theatlantic.com/games/bracket-city") + "\n")
+	label := lipgloss.NewStyle().Width(16)
+	if path, err := configPath(); err == nil {
+		b.WriteString(label.Render(tr("Config:")) + path + "\n")
+	}
+	if m.store != nil {
+		b.WriteString(label.Render(tr("Database:")) + m.store.path + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(noticeStyle.Render("esc: " + tr("back") + " • " + helpLine(keys.WhatsNew)))
+
+	return b.String()
+}
+
+func writeBindings(b *strings.Builder, bs ...key.Binding) {
+	for _, k := range bs {
+		b.WriteString("  " + lipgloss.NewStyle().Width(10).Render(k.Help().Key) + tr(k.Help().Desc) + "\n")
+	}
+}
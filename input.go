@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// inputActions are the answer input's editing actions that can be
+// rebound in the config's [input_keys] table, by name. The defaults
+// are readline's (ctrl+w, ctrl+u, alt+b, alt+f and so on).
+var inputActions = map[string]func(*textinput.KeyMap) *key.Binding{
+	"char_forward":         func(km *textinput.KeyMap) *key.Binding { return &km.CharacterForward },
+	"char_backward":        func(km *textinput.KeyMap) *key.Binding { return &km.CharacterBackward },
+	"word_forward":         func(km *textinput.KeyMap) *key.Binding { return &km.WordForward },
+	"word_backward":        func(km *textinput.KeyMap) *key.Binding { return &km.WordBackward },
+	"delete_word_backward": func(km *textinput.KeyMap) *key.Binding { return &km.DeleteWordBackward },
+	"delete_word_forward":  func(km *textinput.KeyMap) *key.Binding { return &km.DeleteWordForward },
+	"delete_after_cursor":  func(km *textinput.KeyMap) *key.Binding { return &km.DeleteAfterCursor },
+	"delete_before_cursor": func(km *textinput.KeyMap) *key.Binding { return &km.DeleteBeforeCursor },
+	"delete_char_backward": func(km *textinput.KeyMap) *key.Binding { return &km.DeleteCharacterBackward },
+	"delete_char_forward":  func(km *textinput.KeyMap) *key.Binding { return &km.DeleteCharacterForward },
+	"line_start":           func(km *textinput.KeyMap) *key.Binding { return &km.LineStart },
+	"line_end":             func(km *textinput.KeyMap) *key.Binding { return &km.LineEnd },
+	"paste":                func(km *textinput.KeyMap) *key.Binding { return &km.Paste },
+}
+
+// checkInputKeys returns an error for an unknown action in the config's
+// input keys.
+func checkInputKeys(bindings map[string][]string) error {
+	for name := range bindings {
+		if _, ok := inputActions[name]; !ok {
+			return fmt.Errorf("unknown input action %q (expected one of %v)", name, sortedInputActions())
+		}
+	}
+	return nil
+}
+
+// sortedInputActions returns the names of the input actions, sorted.
+func sortedInputActions() []string {
+	names := make([]string, 0, len(inputActions))
+	for n := range inputActions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newInput creates a text input with the config's key bindings. An
+// action bound to no keys is turned off.
+func newInput(cfg config) textinput.Model {
+	in := textinput.New()
+	for name, ks := range cfg.InputKeys {
+		if action, ok := inputActions[name]; ok {
+			*action(&in.KeyMap) = key.NewBinding(key.WithKeys(slices.Clone(ks)...))
+		}
+	}
+	return in
+}
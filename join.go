@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var _ tea.Model = joinModel{}
+
+// teamClosedMsg is sent when the host ends the team session.
+type teamClosedMsg struct{}
+
+// joinModel plays in a team session someone else is hosting: it shows
+// the host's game, and sends the player's guesses to it.
+type joinModel struct {
+	name   string
+	conn   net.Conn
+	msgs   <-chan teamMsg
+	state  teamMsg
+	status string
+	closed bool
+	txtin  textinput.Model
+	w      int
+}
+
+// joinTeam connects to a team session, returning the game once the
+// host has let the player in.
+func joinTeam(addr, name string, cfg config) (joinModel, error) {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return joinModel{}, err
+	}
+	if err := json.NewEncoder(conn).Encode(teamMsg{Type: "hello", Name: name}); err != nil {
+		conn.Close()
+		return joinModel{}, err
+	}
+
+	// The host answers with the game, or why the player can't join
+	sc := bufio.NewScanner(conn)
+	var first teamMsg
+	if !sc.Scan() {
+		conn.Close()
+		return joinModel{}, errors.New("the host closed the connection")
+	}
+	if err := json.Unmarshal(sc.Bytes(), &first); err != nil {
+		conn.Close()
+		return joinModel{}, fmt.Errorf("unexpected message from the host: %w", err)
+	}
+	if first.Type == "error" {
+		conn.Close()
+		return joinModel{}, fmt.Errorf("couldn't join: %s", first.Error)
+	}
+
+	msgs := make(chan teamMsg)
+	go func() {
+		defer close(msgs)
+		for sc.Scan() {
+			var msg teamMsg
+			if json.Unmarshal(sc.Bytes(), &msg) == nil {
+				msgs <- msg
+			}
+		}
+	}()
+
+	tin := newInput(cfg)
+	tin.Focus()
+	return joinModel{
+		name:  teamName(name),
+		conn:  conn,
+		msgs:  msgs,
+		state: first,
+		txtin: tin,
+	}, nil
+}
+
+// listen waits for the next message from the host.
+func (m joinModel) listen() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-m.msgs
+		if !ok {
+			return teamClosedMsg{}
+		}
+		return msg
+	}
+}
+
+func (m joinModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.listen())
+}
+
+func (m joinModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w = msg.Width
+
+	case teamMsg:
+		switch msg.Type {
+		case "state":
+			// Say who guessed what, when there's a new guess
+			if l := msg.Last; l != nil && (m.state.Last == nil || !l.At.Equal(m.state.Last.At)) {
+				switch {
+				case l.By == m.name && l.Kind == "correct":
+					m.status = tr("✅ You got %q", l.Guess)
+				case l.By == m.name:
+					m.status = errorStyle.Render(tr("❌ %q is wrong", l.Guess))
+				case l.Kind == "correct":
+					m.status = tr("👥 %s got %q", l.By, l.Guess)
+				default:
+					m.status = tr("👥 %s guessed %q, which is wrong", l.By, l.Guess)
+				}
+			}
+			m.state = msg
+		case "error":
+			m.status = errorStyle.Render(msg.Error)
+		}
+		return m, m.listen()
+
+	case teamClosedMsg:
+		m.closed = true
+		m.status = errorStyle.Render(tr("The host ended the team game"))
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit), msg.Type == tea.KeyEsc:
+			m.conn.Close()
+			return m, tea.Quit
+		case key.Matches(msg, keys.Submit):
+			in := strings.TrimSpace(m.txtin.Value())
+			if in == "" || m.closed {
+				return m, nil
+			}
+			m.txtin.Reset()
+			m.status = ""
+			if err := json.NewEncoder(m.conn).Encode(teamMsg{Type: "guess", Answer: in}); err != nil {
+				m.status = errorStyle.Render(tr("Couldn't send the guess: %v", err))
+			}
+			return m, nil
+		}
+		tin, cmd := m.txtin.Update(msg)
+		m.txtin = tin
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m joinModel) View() string {
+	g := m.state.Game
+	if g == nil {
+		return tr("Waiting for the host...")
+	}
+	width := min(max(m.w, 20), maxBodyWidth)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("[ Bracket City | "+g.Date+" ]") + "\n")
+	fmt.Fprintf(&b, "✅ %d/%d ❌ %d\n", g.Correct, g.Total, g.Incorrect)
+	b.WriteString("---\n")
+	b.WriteString(renderSegments(parseSegments(g.Puzzle), width) + "\n")
+	b.WriteString("---\n")
+	switch {
+	case g.GaveUp:
+		b.WriteString(tr("🏳️ The host gave up") + "\n\n")
+	case g.Done:
+		b.WriteString(tr("🎉 You win! 🎉") + "\n\n")
+	default:
+		b.WriteString(m.txtin.View() + "\n")
+	}
+	if t := teamView(m.state.Players); t != "" && g.Done {
+		b.WriteString(t + "\n\n")
+	}
+	help := "esc: " + tr("leave")
+	if !g.Done {
+		help = helpLine(keys.Submit) + " • " + help
+	}
+	b.WriteString(noticeStyle.Render(help))
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
+	}
+	b.WriteString("\n\n" + noticeStyle.Render(tr("👥 Playing in a team game as %s", m.name)))
+	return b.String()
+}
+
+// runJoin joins the team session at addr and plays in it.
+func runJoin(addr, name string) error {
+	if err := requireTerminal(); err != nil {
+		return err
+	}
+	cfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	setLocale(cfg.Locale)
+	m, err := joinTeam(addr, name, cfg)
+	if err != nil {
+		return err
+	}
+	defer m.conn.Close()
+	setTheme(cfg.Theme)
+	_, err = runViews(m)
+	return err
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keymap holds the game's key bindings.
+type keymap struct {
+	// While playing
+	Submit    key.Binding
+	Dismiss   key.Binding
+	Streamer  key.Binding
+	Info      key.Binding
+	Pause     key.Binding
+	Retry     key.Binding
+	GiveUp    key.Binding
+	Helper    key.Binding
+	Palette   key.Binding
+	Launcher  key.Binding
+	Focus     key.Binding
+	SwitchTab key.Binding
+	Today     key.Binding
+	Quit      key.Binding
+
+	// On the results screen
+	PlayAgain key.Binding
+	Next      key.Binding
+	Open      key.Binding
+	CopyURL   key.Binding
+	Share     key.Binding
+	Diff      key.Binding
+	Timing    key.Binding
+	Lookup    key.Binding
+	Note      key.Binding
+	Up        key.Binding
+	Down      key.Binding
+
+	// On the calendar
+	Left   key.Binding
+	Right  key.Binding
+	Zoom   key.Binding
+	Legend key.Binding
+	Close  key.Binding
+
+	// On the trends screen
+	Metric key.Binding
+	Window key.Binding
+
+	// On the rules & info screen
+	WhatsNew key.Binding
+}
+
+var keys = keymap{
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "submit answer"),
+	),
+	Dismiss: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "dismiss notice"),
+	),
+	Streamer: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "toggle streamer mode"),
+	),
+	Info: key.NewBinding(
+		// Terminals send tab for ctrl+i. A plain "i" would be taken
+		// from answers starting with it.
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "rules & info"),
+	),
+	Pause: key.NewBinding(
+		key.WithKeys("ctrl+z"),
+		key.WithHelp("ctrl+z", "pause"),
+	),
+	Retry: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "retry"),
+	),
+	GiveUp: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("ctrl+g", "give up"),
+	),
+	Helper: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "letter helper"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys(":"),
+		key.WithHelp(":", "command palette"),
+	),
+	Launcher: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "go to..."),
+	),
+	Focus: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "switch pane"),
+	),
+	SwitchTab: key.NewBinding(
+		key.WithKeys(
+			"1", "2", "3", "4", "5", "6", "7", "8", "9",
+			"alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9",
+		),
+		key.WithHelp("1-9", "switch tab"),
+	),
+	Today: key.NewBinding(
+		key.WithKeys("t", "alt+t"),
+		key.WithHelp("alt+t", "today's puzzle"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+	PlayAgain: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "play again"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "play the suggested puzzle"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in browser"),
+	),
+	CopyURL: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "copy URL"),
+	),
+	Share: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "copy share text"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "compare to solution"),
+	),
+	Timing: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "clue timings"),
+	),
+	Lookup: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "look up a word"),
+	),
+	Note: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit note"),
+	),
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓", "down"),
+	),
+	Left: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←", "previous"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→", "next"),
+	),
+	Zoom: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "zoom"),
+	),
+	Legend: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "hide legend"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("q", "esc"),
+		key.WithHelp("q", "quit"),
+	),
+	Metric: key.NewBinding(
+		key.WithKeys("m", "tab"),
+		key.WithHelp("m", "next metric"),
+	),
+	Window: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "time window"),
+	),
+	WhatsNew: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "what's new"),
+	),
+}
+
+// helpLine formats short help for the bindings, e.g.
+// "r: play again • q: quit".
+func helpLine(bs ...key.Binding) string {
+	var parts []string
+	for _, b := range bs {
+		parts = append(parts, b.Help().Key+": "+tr(b.Help().Desc))
+	}
+	return strings.Join(parts, " • ")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// launchItem is an entry in the launcher: an action, or a puzzle to
+// play, run as a palette command.
+type launchItem struct {
+	title, desc string
+	command     string
+}
+
+func (i launchItem) Title() string       { return i.title }
+func (i launchItem) Description() string { return i.desc }
+func (i launchItem) FilterValue() string { return i.title }
+
+// launchItems lists everything the launcher can do: the palette
+// commands, then the cached puzzles, newest first.
+func (m model) launchItems() []list.Item {
+	var items []list.Item
+	for _, c := range paletteCommands {
+		switch c.name {
+		case "date":
+			items = append(items, launchItem{tr("Play today's puzzle"), tr(c.desc), "date"})
+		case "tab":
+			// Puzzles are listed below
+			continue
+		case "theme":
+			items = append(items,
+				launchItem{tr("Dark theme"), tr(c.desc), "theme dark"},
+				launchItem{tr("Light theme"), tr(c.desc), "theme light"},
+			)
+		default:
+			items = append(items, launchItem{tr(c.desc), ":" + c.name, c.name})
+		}
+	}
+
+	if m.store == nil {
+		return items
+	}
+	var dates []string
+	for _, p := range m.store.cachedPuzzles() {
+		if _, err := time.Parse(time.DateOnly, p.PuzzleDate); err == nil {
+			dates = append(dates, p.PuzzleDate)
+		}
+	}
+	slices.Sort(dates)
+	slices.Reverse(dates)
+	for _, d := range dates {
+		desc := tr("not played")
+		if gs, ok := m.store.game(d); ok {
+			desc = gameStatus(gs)
+		}
+		items = append(items, launchItem{tr("Play %s", d), desc, "date " + d})
+	}
+	return items
+}
+
+// openLauncher opens the launcher, ready to type a filter.
+func (m *model) openLauncher() {
+	l := list.New(m.launchItems(), list.NewDefaultDelegate(), m.bodyWidth(), max(m.h-2, 10))
+	l.Title = tr("Go to...")
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilterText("")
+	l.SetFilterState(list.Filtering)
+	m.launcher = l
+	m.launching = true
+}
+
+// updateLauncher handles messages while the launcher is open.
+func (m model) updateLauncher(msg tea.Msg) (model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.Dismiss):
+			m.launching = false
+			return m, nil
+		case key.Matches(msg, keys.Submit):
+			m.launching = false
+			if it, ok := m.launcher.SelectedItem().(launchItem); ok {
+				return m.runCommand(it.command)
+			}
+			return m, nil
+
+		// The list doesn't move while filtering, so move it here
+		case msg.Type == tea.KeyUp:
+			m.launcher.CursorUp()
+			return m, nil
+		case msg.Type == tea.KeyDown:
+			m.launcher.CursorDown()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.launcher, cmd = m.launcher.Update(msg)
+	return m, cmd
+}
+
+// launcherView renders the launcher.
+func (m model) launcherView() string {
+	move := key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "move"))
+	run := keys.Submit
+	run.SetHelp("enter", "go")
+	closeKey := keys.Dismiss
+	closeKey.SetHelp("esc", "close")
+	return strings.TrimRight(m.launcher.View(), "\n") + "\n\n" +
+		noticeStyle.Render(helpLine(move, run, closeKey))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compactWidth is the width below which the game drops inessentials,
+// like the puzzle's size and most of the key help.
+const compactWidth = 60
+
+// bodyWidth is the width the game's content is laid out in: the
+// window's, up to the configured maximum.
+func (m model) bodyWidth() int {
+	limit := m.cfg.MaxWidth
+	if limit <= 0 {
+		limit = maxBodyWidth
+	}
+	if m.w <= 0 {
+		return limit
+	}
+	return min(m.w, limit)
+}
+
+// compact reports whether the window is narrow enough to lay the game
+// out more sparely.
+func (m model) compact() bool {
+	return m.w > 0 && m.w < compactWidth
+}
+
+// centered centers the content as a block in a window wider than the
+// maximum width, unless that's turned off.
+func (m model) centered(v string) string {
+	if m.cfg.NoCenter || m.w <= m.bodyWidth() {
+		return v
+	}
+	block := lipgloss.NewStyle().Width(m.bodyWidth()).Render(v)
+	return lipgloss.PlaceHorizontal(m.w, lipgloss.Center, block)
+}
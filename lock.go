@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gameLock is the lock file marking a date's puzzle as open in a brack
+// process, so another one (e.g. in a second tmux pane) opens it
+// read-only rather than overwriting its saves.
+type gameLock struct {
+	PID   int       `json:"pid"`
+	Host  string    `json:"host"`
+	Since time.Time `json:"since"`
+}
+
+// lockMaxAge is how long a lock from another machine (with a database
+// on a shared drive) is honored, since its process can't be checked.
+const lockMaxAge = 12 * time.Hour
+
+// heldLocks are the paths of the lock files this process holds, to
+// remove on exit (see releaseLocks). Games can be opened from commands
+// running in the background, so it's guarded by heldMu.
+var (
+	heldLocks = make(map[string]bool)
+	heldMu    sync.Mutex
+)
+
+func (l gameLock) String() string {
+	return fmt.Sprintf("pid %d on %s", l.PID, l.Host)
+}
+
+// live reports whether the process holding the lock is still running.
+func (l gameLock) live() bool {
+	host, _ := os.Hostname()
+	if l.Host != host {
+		return time.Since(l.Since) < lockMaxAge
+	}
+	p, err := os.FindProcess(l.PID)
+	if err != nil {
+		return false
+	}
+	// On Windows, finding the process opens it, which fails once it's
+	// gone. Elsewhere, signal 0 checks it exists.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// lockPath returns the path of the lock file for a date's puzzle, in a
+// locks directory beside the database.
+func (s *store) lockPath(date string) string {
+	name := strings.TrimSuffix(filepath.Base(s.path), filepath.Ext(s.path))
+	return filepath.Join(filepath.Dir(s.path), "locks", name+"."+date+".lock")
+}
+
+// holds reports whether this process holds the lock on a date.
+func (s *store) holds(date string) bool {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	return heldLocks[s.lockPath(date)]
+}
+
+// lock takes the lock on a date's puzzle for this process, unless
+// another running brack has it, in which case its lock is returned
+// with ok false. The lock file is created exclusively, so two processes
+// can't both take it; one left behind by a process that's gone is
+// removed and taken over.
+func (s *store) lock(date string) (holder gameLock, ok bool, err error) {
+	path := s.lockPath(date)
+	if s.path == memoryPath || s.holds(date) {
+		return holder, true, nil
+	}
+
+	host, _ := os.Hostname()
+	b, err := json.Marshal(gameLock{PID: os.Getpid(), Host: host, Since: time.Now()})
+	if err != nil {
+		return holder, false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return holder, false, err
+	}
+	// Try again once after taking over a stale lock. If another process
+	// gets in first, it's theirs.
+	for range 2 {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = f.Write(b)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				os.Remove(path)
+				return holder, false, err
+			}
+			heldMu.Lock()
+			heldLocks[path] = true
+			heldMu.Unlock()
+			return gameLock{}, true, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return holder, false, err
+		}
+
+		holder = gameLock{}
+		held, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Released in the meantime
+			continue
+		} else if err != nil {
+			return holder, false, err
+		}
+		if json.Unmarshal(held, &holder) == nil && holder.PID != os.Getpid() && holder.live() {
+			return holder, false, nil
+		}
+		slog.Debug("taking over stale lock", "path", path, "pid", holder.PID)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return holder, false, err
+		}
+	}
+	if held, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(held, &holder)
+	}
+	return holder, false, nil
+}
+
+// unlock gives up this process's lock on a date's puzzle, if it holds
+// it.
+func (s *store) unlock(date string) {
+	path := s.lockPath(date)
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	if !heldLocks[path] {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Error("failed to remove lock", "path", path, "err", err)
+	}
+	delete(heldLocks, path)
+}
+
+// retryLock reloads the puzzle with the other process's progress, and
+// tries again to take the lock on it.
+func (m model) retryLock() (model, tea.Cmd) {
+	m.store.mergeFromDisk()
+	n, cmd := m.switchPuzzle(m.data, false)
+	if n.lockedBy != "" {
+		n.toast = tr("The puzzle is still open in another brack, this is its progress so far")
+	} else {
+		n.toast = tr("The other brack closed the puzzle, so it's yours to play")
+	}
+	return n, cmd
+}
+
+// releaseLocks removes the lock files this process holds.
+func releaseLocks() {
+	heldMu.Lock()
+	defer heldMu.Unlock()
+	for path := range heldLocks {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Error("failed to remove lock", "path", path, "err", err)
+		}
+		delete(heldLocks, path)
+	}
+}
+
+// mergeFromDisk takes in changes another brack process has written to
+// the database since this one last read or wrote it, before writing
+// over them. Games on dates this process has locked are its own, and
+// every other date's are taken from the disk. Anything else only
+// on the disk (cached puzzles, notes and so on) is added.
+func (s *store) mergeFromDisk() {
+	fi, err := os.Stat(s.path)
+	if err != nil || fi.ModTime().Equal(s.modTime) {
+		return
+	}
+	disk, err := readStoreFile(s.path)
+	if err != nil {
+		slog.Error("failed to read the database to merge", "path", s.path, "err", err)
+		return
+	}
+	slog.Debug("merging changes from another process", "path", s.path)
+
+	for date := range s.data.Games {
+		if _, ok := disk.Games[date]; !ok && !s.holds(date) {
+			delete(s.data.Games, date)
+		}
+	}
+	for date, gs := range disk.Games {
+		if !s.holds(date) {
+			s.data.Games[date] = gs
+		}
+	}
+	for date := range s.data.Attempts {
+		if _, ok := disk.Attempts[date]; !ok && !s.holds(date) {
+			delete(s.data.Attempts, date)
+		}
+	}
+	for date, as := range disk.Attempts {
+		if !s.holds(date) {
+			s.data.Attempts[date] = as
+		}
+	}
+	addMissing(s.data.Metadata, disk.Metadata)
+	addMissing(s.data.Puzzles, disk.Puzzles)
+	addMissing(s.data.RawPuzzles, disk.RawPuzzles)
+	addMissing(s.data.Cards, disk.Cards)
+	addMissing(s.data.Notes, disk.Notes)
+}
+
+// readStoreFile reads a database file as it is on disk, without
+// migrating it.
+func readStoreFile(path string) (storedata, error) {
+	var data storedata
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return data, err
+	}
+	if b, err = decryptStore(b); err != nil {
+		return data, err
+	}
+	err = json.Unmarshal(b, &data)
+	return data, err
+}
+
+// addMissing copies the entries of from that to doesn't have.
+func addMissing[V any](to, from map[string]V) {
+	for k, v := range from {
+		if _, ok := to[k]; !ok {
+			to[k] = v
+		}
+	}
+}
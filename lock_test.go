@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeLock leaves a lock file as another process would.
+func writeLock(t *testing.T, s *store, date string, l gameLock) {
+	t.Helper()
+	b, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := s.lockPath(date)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLock(t *testing.T) {
+	t.Cleanup(releaseLocks)
+	s, err := openStore(filepath.Join(t.TempDir(), "brack.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, _ := os.Hostname()
+
+	// A free date is taken, and taking it again is fine
+	for range 2 {
+		if _, ok, err := s.lock("2025-03-01"); err != nil || !ok {
+			t.Fatalf("lock of a free date: ok %v, err %v", ok, err)
+		}
+	}
+	if !s.holds("2025-03-01") {
+		t.Error("the lock isn't recorded as held")
+	}
+
+	// The test runner is still running, so its lock is honored
+	other := gameLock{PID: os.Getppid(), Host: host, Since: time.Now()}
+	writeLock(t, s, "2025-03-02", other)
+	holder, ok, err := s.lock("2025-03-02")
+	if err != nil || ok {
+		t.Fatalf("lock held by a live process: ok %v, err %v", ok, err)
+	}
+	if holder.PID != other.PID {
+		t.Errorf("holder is %v, want %v", holder, other)
+	}
+
+	// Locks left by a process that's gone, or from another machine long
+	// ago, are taken over
+	writeLock(t, s, "2025-03-03", gameLock{PID: 1 << 30, Host: host, Since: time.Now()})
+	writeLock(t, s, "2025-03-04", gameLock{PID: os.Getppid(), Host: "elsewhere", Since: time.Now().Add(-2 * lockMaxAge)})
+	for _, date := range []string{"2025-03-03", "2025-03-04"} {
+		if _, ok, err := s.lock(date); err != nil || !ok {
+			t.Errorf("lock on %s with a stale lock: ok %v, err %v", date, ok, err)
+		}
+		b, err := os.ReadFile(s.lockPath(date))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var l gameLock
+		if err := json.Unmarshal(b, &l); err != nil || l.PID != os.Getpid() {
+			t.Errorf("lock on %s is %s (%v), want this process's", date, b, err)
+		}
+	}
+
+	releaseLocks()
+	if _, err := os.Stat(s.lockPath("2025-03-01")); !os.IsNotExist(err) {
+		t.Errorf("the lock file is left after releasing it: %v", err)
+	}
+}
+
+func TestMergeFromDisk(t *testing.T) {
+	t.Cleanup(releaseLocks)
+	path := filepath.Join(t.TempDir(), "brack.json")
+	a, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := a.lock("2025-03-01"); err != nil || !ok {
+		t.Fatalf("lock: ok %v, err %v", ok, err)
+	}
+	if err := a.saveGame(gamestate{Date: "2025-03-01", Correct: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another brack saves over the locked game, and plays another day
+	b, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.data.Games["2025-03-01"] = gamestate{Date: "2025-03-01", Correct: 9}
+	b.data.Notes["2025-03-02"] = "tricky"
+	if err := b.saveGame(gamestate{Date: "2025-03-02", Correct: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	a.mergeFromDisk()
+	if gs, _ := a.game("2025-03-01"); gs.Correct != 2 {
+		t.Errorf("the locked game was taken from the disk: %+v", gs)
+	}
+	if gs, ok := a.game("2025-03-02"); !ok || gs.Correct != 5 {
+		t.Errorf("the other game wasn't taken from the disk: %+v", gs)
+	}
+	if n := a.data.Notes["2025-03-02"]; n != "tricky" {
+		t.Errorf("the note wasn't added from the disk: %q", n)
+	}
+
+	// And writing keeps both
+	if err := a.saveGame(gamestate{Date: "2025-03-01", Correct: 3}); err != nil {
+		t.Fatal(err)
+	}
+	disk, err := readStoreFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disk.Games["2025-03-01"].Correct != 3 || disk.Games["2025-03-02"].Correct != 5 {
+		t.Errorf("the database has %+v", disk.Games)
+	}
+}
+
+// Reset and clean leave alone the dates another brack has open, as it
+// would save over them.
+func TestResetLocked(t *testing.T) {
+	t.Cleanup(releaseLocks)
+	s, err := openStore(filepath.Join(t.TempDir(), "brack.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.saveGame(gamestate{Date: "2024-01-01", Correct: 1}); err != nil {
+		t.Fatal(err)
+	}
+	host, _ := os.Hostname()
+	writeLock(t, s, "2024-01-01", gameLock{PID: os.Getppid(), Host: host, Since: time.Now()})
+
+	d := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	if err := runReset(io.Discard, s, d, true); !errors.Is(err, errPuzzleLocked) {
+		t.Errorf("reset of a locked date: %v", err)
+	}
+	if err := runClean(io.Discard, s, 24*time.Hour, false, true); !errors.Is(err, errPuzzleLocked) {
+		t.Errorf("clean of a locked date: %v", err)
+	}
+	if _, ok := s.game("2024-01-01"); !ok {
+		t.Fatal("the locked game was deleted")
+	}
+
+	// Once it's closed, it can be reset
+	if err := os.Remove(s.lockPath("2024-01-01")); err != nil {
+		t.Fatal(err)
+	}
+	if err := runReset(io.Discard, s, d, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.game("2024-01-01"); ok {
+		t.Error("reset kept the game")
+	}
+}
+
+// Switching puzzles gives up the old one's lock, but opening one in
+// another tab doesn't.
+func TestSwitchPuzzleUnlocks(t *testing.T) {
+	t.Cleanup(releaseLocks)
+	p, err := loadDemoPuzzle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := openStore(filepath.Join(t.TempDir(), "brack.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := defaultConfig()
+	cfg.CheckForUpdates = false
+	m := newModel(p, s, cfg)
+	m.stopTimer()
+	other := p
+	other.PuzzleDate = "2025-01-02"
+
+	m.openPuzzle(other, false)
+	if !s.holds(p.PuzzleDate) || !s.holds(other.PuzzleDate) {
+		t.Errorf("after opening a tab, the locks held are %v", heldLocks)
+	}
+	s.unlock(other.PuzzleDate)
+	m.switchPuzzle(other, false)
+	if s.holds(p.PuzzleDate) || !s.holds(other.PuzzleDate) {
+		t.Errorf("after switching puzzles, the locks held are %v", heldLocks)
+	}
+}
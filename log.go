@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setupLogging configures the default slog logger.
+//
+// When debug is false logs are discarded (anything written to stderr
+// would corrupt the TUI). Otherwise structured logs are written to the
+// file at path, which the caller should close when done.
+func setupLogging(debug bool, path string) (io.Closer, error) {
+	if !debug {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return io.NopCloser(nil), nil
+	}
+
+	f, err := tea.LogToFile(path, "")
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})))
+	slog.Info("debug logging enabled", "version", version)
+	return f, nil
+}
@@ -3,18 +3,23 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
 	"os"
 	"strconv"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/urfave/cli/v3"
 )
 
+const version = "0.0.3"
+
 func main() {
+	var logf io.Closer
 	cmd := &cli.Command{
 		Name:      "brack",
-		Version:   "0.0.3",
+		Version:   version,
 		Usage:     "Play Bracket City on the command line.",
 		ArgsUsage: "[DATE]",
 		Description: `Play Bracket City, by the Atlantic.
@@ -35,30 +40,909 @@ $ brack 2024-01-02
 $ # Play the puzzle for the previous day
 $ brack -1
 
+With --pipe, the game's state is written to stdout as a line of JSON,
+and each line read from stdin is a guess, answered with the state after
+it (or {"error": "..."}), for an editor plugin to render the puzzle. A
+blank line writes the state again.
+
 Bracket City: https://theatlantic.com/games/bracket-city
 		`,
-		Action: func(ctx context.Context, cmd *cli.Command) error {
-			// Is there a date argument?
-			d, err := parseDateArg(cmd.Args().Get(0))
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "debug",
+				Usage: "write debug logs to the log file",
+			},
+			&cli.StringFlag{
+				Name:  "log-file",
+				Usage: "path of the debug log file",
+				Value: "brack-debug.log",
+			},
+			&cli.BoolFlag{
+				Name:  "pipe",
+				Usage: "play over stdin and stdout as lines of JSON, for an editor plugin",
+			},
+			&cli.BoolFlag{
+				Name:  "no-save",
+				Usage: "play without saving any games, stats or settings",
+			},
+			&cli.BoolFlag{
+				Name:  "insecure",
+				Usage: "don't verify TLS certificates (e.g. behind a proxy that intercepts TLS)",
+			},
+		}, overrideFlags()...),
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			f, err := setupLogging(cmd.Bool("debug"), cmd.String("log-file"))
 			if err != nil {
-				return err
+				return ctx, err
 			}
+			logf = f
+			noSave = cmd.Bool("no-save")
 
-			// Fetch the puzzle data
-			puzzle, err := getPuzzleData(d)
+			// A broken config file is reported by the command that
+			// needs it (or brack config check), so use the defaults
+			setFlagOverrides(cmd)
+			cfg, err := loadUserConfig()
 			if err != nil {
-				return err
+				cfg = defaultConfig()
 			}
-
-			// Run the puzzle
-			m := newModel(puzzle)
-			p := tea.NewProgram(m, tea.WithAltScreen())
-			if _, err := p.Run(); err != nil {
-				return err
+			setEndpoint(cfg.Endpoint)
+			setupEncryption(cfg.EncryptDB, cfg.DBKeyFile)
+			if err := setColorProfile(cfg.Color); err != nil {
+				return ctx, err
+			}
+			if err := setGlyphs(cfg.Glyphs, cfg.NoEmoji); err != nil {
+				return ctx, err
+			}
+			return ctx, setupHTTP(cfg.CABundle, cmd.Bool("insecure"))
+		},
+		After: func(ctx context.Context, cmd *cli.Command) error {
+			releaseLocks()
+			if logf == nil {
+				return nil
+			}
+			return logf.Close()
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Bool("pipe") {
+				d, err := parseDateArg(cmd.Args().Get(0))
+				if err != nil {
+					return err
+				}
+				s, cfg, err := openUserGames()
+				if err != nil {
+					return err
+				}
+				return runPipe(os.Stdin, os.Stdout, newEngine(s, cfg), d.Format(time.DateOnly))
 			}
+			return runGame(cmd.Args().Get(0), nil, nil)
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "calendar",
+				Usage: "Browse past puzzles and pick one to play.",
+				Description: `Show a year at a glance, like a contribution graph, with each day
+colored by how its puzzle went. Zoom into a month (z or enter) and
+press enter on a day to play its puzzle.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runCalendar()
+				},
+			},
+			{
+				Name:  "warmup",
+				Usage: "Play a quick warm-up puzzle of clues you've solved before.",
+				Description: `Play a tiny puzzle of two or three clues from puzzles you've solved,
+as a warm-up. It isn't saved or counted in your stats. Press t when
+you're done to go on to today's puzzle.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runWarmup()
+				},
+			},
+			{
+				Name:  "review",
+				Usage: "Review clues from puzzles you've solved, as flashcards.",
+				Description: `Quiz yourself on clues from the puzzles you've solved: each clue is
+shown and you type its answer. Clues are scheduled for review with
+SM-2 spaced repetition, so the ones you miss come back sooner. Each
+session has the clues due that day, plus a few new ones.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runReview()
+				},
+			},
+			{
+				Name:  "demo",
+				Usage: "Replay a scripted demo against a bundled puzzle.",
+				Description: `Replay a scripted sequence of keypresses against a bundled puzzle,
+for producing reproducible recordings (e.g. with VHS).
+
+Example:
+
+$ brack demo --script demo/demo.yaml`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "script",
+						Usage:    "path to the demo script (YAML)",
+						Required: true,
+					},
+					&cli.Uint64Flag{
+						Name:  "seed",
+						Usage: "seed for any randomized features",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					seedRand(cmd.Uint64("seed"))
+					return runDemo(cmd.String("script"))
+				},
+			},
+			{
+				Name:  "upgrade",
+				Usage: "Upgrade brack to the latest release.",
+				Description: `Check GitHub for a newer release of brack and, if there is one,
+download the binary for this platform, verify its checksum, and
+replace the current executable with it.`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "check",
+						Usage: "only check whether a newer version is available",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runUpgrade(os.Stdout, cmd.Bool("check"))
+				},
+			},
+			{
+				Name:  "man",
+				Usage: "Print the brack man page.",
+				Description: `Print a man page for brack, in roff format.
+
+Example:
+
+$ brack man > /usr/local/share/man/man1/brack.1`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					writeManPage(os.Stdout, cmd.Root())
+					return nil
+				},
+			},
+			{
+				Name:  "doctor",
+				Usage: "Report environment diagnostics.",
+				Description: `Report on brack's environment: the config file, the database,
+whether the puzzle API is reachable, and the terminal's capabilities.
+Please include this output when reporting a bug.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runDoctor(os.Stdout)
+				},
+			},
+			{
+				Name:      "broadcast",
+				Usage:     "Play a puzzle while others watch along.",
+				ArgsUsage: "[DATE]",
+				Description: `Play the puzzle for DATE (as for brack itself) while serving a live,
+read-only view of the game over HTTP, for a friend or an OBS browser
+source to watch. Open the address shown in the footer to watch; the
+state is also available as JSON at /state and as a stream of
+server-sent events at /events.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "address to serve the broadcast on",
+						Value: "localhost:8642",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					bc, stop, err := startBroadcast(cmd.String("listen"))
+					if err != nil {
+						return err
+					}
+					defer stop()
+					return runGame(cmd.Args().Get(0), bc, nil)
+				},
+			},
+			{
+				Name:  "serve",
+				Usage: "Serve your puzzles and games as a JSON API.",
+				Description: `Serve the puzzles and your games over HTTP as JSON, so another front
+end (a web or mobile app, say) can play with the same games and stats
+as the game itself. Puzzles come from the cache, and are fetched if
+they aren't there. A puzzle's game is locked while the server runs
+once it's been requested, so the game opens it read-only.
+
+  GET  /puzzles/DATE          the puzzle, without its answers
+  GET  /games/DATE            the game's state, as for brack guess --json
+  POST /games/DATE/guesses    make a guess, with a body of {"answer": "..."}
+
+DATE is YYYY-MM-DD, or a negative number of days back from today.
+Errors are returned as {"error": "..."}.
+
+Web pages can only call the API from the origins given with
+--allow-origin (or any, with "*"); requests from other pages are
+refused, so other sites open in the browser can't read your games or
+make guesses.
+
+Example:
+
+$ brack serve --http :8080 --allow-origin http://localhost:3000`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "http",
+						Usage: "address to serve the API on",
+						Value: "localhost:8080",
+					},
+					&cli.StringSliceFlag{
+						Name:  "allow-origin",
+						Usage: "origin of web pages allowed to call the API (repeatable)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					s, cfg, err := openUserGames()
+					if err != nil {
+						return err
+					}
+					return runServe(ctx, os.Stdout, cmd.String("http"), cmd.StringSlice("allow-origin"), s, cfg)
+				},
+			},
+			{
+				Name:  "rpc",
+				Usage: "Play over stdin and stdout, for other front ends.",
+				Description: `Answer JSON-RPC 2.0 requests on stdin, one per line, with responses
+on stdout, so another front end (a GUI or an editor plugin, say) can
+run brack as a process and play through it. It runs until stdin is
+closed.
+
+Methods:
+
+  version                  {"brack": "` + version + `", "protocol": 1}
+  puzzle  {date}           the puzzle, without its answers
+  game    {date}           the game's state, as for brack guess --json
+  guess   {date, answer}   make a guess, returning the same as brack guess --json
+
+A date is YYYY-MM-DD, or a negative number of days back from today.
+Besides the standard JSON-RPC error codes, a puzzle that's open in
+another brack is error 1, a finished puzzle is error 2, and a guess
+during the hard mode cooldown is error 3, with the seconds to wait as
+{"retry_after": N} in its data.
+
+Example:
+
+$ echo '{"jsonrpc": "2.0", "id": 1, "method": "game", "params": {"date": "2024-03-01"}}' | brack rpc`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					s, cfg, err := openUserGames()
+					if err != nil {
+						return err
+					}
+					return runRPC(os.Stdin, os.Stdout, newEngine(s, cfg))
+				},
+			},
+			{
+				Name:  "bot",
+				Usage: "Run a chat bot for a channel to play together.",
+				Commands: []*cli.Command{
+					{
+						Name:  "discord",
+						Usage: "Run a Discord bot with a /brack command.",
+						Description: `Serve a Discord bot's interactions endpoint, so a channel can play
+today's puzzle together with /brack show and /brack guess ANSWER. Each
+channel plays its own game, kept in a profile of its own (e.g.
+discord-1234), apart from yours.
+
+Create an application with a bot in the Discord developer portal, put
+its token in the config file as discord_token, and set the
+application's interactions endpoint URL to a public HTTPS address that
+reaches --http (through a reverse proxy or a tunnel, say). The /brack
+command is registered each time the bot starts.
+
+Example:
+
+$ brack bot discord --http :8081`,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "http",
+								Usage: "address to serve the interactions endpoint on",
+								Value: "localhost:8081",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							cfg, err := loadUserConfig()
+							if err != nil {
+								return err
+							}
+							return runDiscordBot(ctx, os.Stdout, cmd.String("http"), cfg)
+						},
+					},
+					{
+						Name:  "slack",
+						Usage: "Answer a Slack /brack slash command.",
+						Description: `Serve a Slack slash command, so a channel can play today's puzzle
+together with /brack show and /brack guess ANSWER. Each channel plays
+its own game, kept in a profile of its own (e.g. slack-C1234), apart
+from yours.
+
+Create a Slack app with a /brack slash command whose request URL is a
+public HTTPS address that reaches --http (through a reverse proxy or a
+tunnel, say), and put the app's signing secret in the config file as
+slack_signing_secret.
+
+Example:
+
+$ brack bot slack --http :8082`,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "http",
+								Usage: "address to serve the slash command on",
+								Value: "localhost:8082",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							cfg, err := loadUserConfig()
+							if err != nil {
+								return err
+							}
+							return runSlackBot(ctx, os.Stdout, cmd.String("http"), cfg)
+						},
+					},
+				},
+			},
+			{
+				Name:      "host",
+				Usage:     "Play a puzzle as a team, with others joining from their terminals.",
+				ArgsUsage: "[DATE]",
+				Description: `Play the puzzle for DATE (as for brack itself) while hosting a team
+session, which others can join from their own terminals with brack
+join. Everyone's guesses go into the host's game, and everyone sees
+every correct answer. Once it's solved, the results show who solved
+what. The game is saved as the host's.
+
+Example:
+
+$ brack host --listen :7000
+$ # And in another terminal, or on another machine:
+$ brack join myhost:7000 --name sam`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "address to host the team session on",
+						Value: ":7000",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "your name in the team",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					name := teamName(cmd.String("name"))
+					if name == "" {
+						name = defaultTeamName()
+					}
+					team, stop, err := startTeamHost(cmd.String("listen"), name)
+					if err != nil {
+						return err
+					}
+					defer stop()
+					return runGame(cmd.Args().Get(0), nil, team)
+				},
+			},
+			{
+				Name:      "join",
+				Usage:     "Join a team game someone is hosting.",
+				ArgsUsage: "ADDRESS",
+				Description: `Join the team game hosted at ADDRESS (host:port) with brack host, to
+play its puzzle together. Your guesses go into the host's game, and
+you see everyone else's correct answers as they come in.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "your name in the team",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("expected exactly one argument, ADDRESS")
+					}
+					name := teamName(cmd.String("name"))
+					if name == "" {
+						name = defaultTeamName()
+					}
+					return runJoin(cmd.Args().First(), name)
+				},
+			},
+			{
+				Name:      "shared",
+				Usage:     "Play a puzzle in a shared file, taking turns with others.",
+				ArgsUsage: "FILE [DATE]",
+				Description: `Play the game kept in FILE, in a folder synced between machines (e.g.
+with Dropbox or Syncthing), so a household can chip away at one
+puzzle, each whenever they have a moment. If FILE doesn't exist yet,
+it's started with the puzzle for DATE (as for brack itself).
+
+Every guess is saved to FILE, on top of anyone else's: if someone
+else saved it first, their progress is loaded instead, and the guess
+can be made again. The game isn't saved to your own games.
+
+Example:
+
+$ brack shared ~/Dropbox/brack.json --name sam`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "your name, to show who solved what",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() < 1 || cmd.Args().Len() > 2 {
+						return fmt.Errorf("expected FILE and an optional DATE")
+					}
+					name := teamName(cmd.String("name"))
+					if name == "" {
+						name = defaultTeamName()
+					}
+					return runShared(cmd.Args().Get(0), cmd.Args().Get(1), name)
+				},
+			},
+			{
+				Name:  "clean",
+				Usage: "Delete old cached puzzles and saved games.",
+				Description: `Delete cached puzzles, saved games and archived attempts for dates
+older than --older-than, to slim down the database. With --puzzles-only,
+games are kept (puzzles are re-fetched as needed).
+
+Example:
+
+$ brack clean --older-than 180d --puzzles-only`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "older-than",
+						Usage:    "age of the data to delete (e.g. 180d, 4w)",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "puzzles-only",
+						Usage: "only delete cached puzzles, keeping saved games",
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "don't ask for confirmation",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					age, err := parseAge(cmd.String("older-than"))
+					if err != nil {
+						return err
+					}
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					return runClean(os.Stdout, s, age, cmd.Bool("puzzles-only"), cmd.Bool("yes"))
+				},
+			},
+			{
+				Name:      "reset",
+				Usage:     "Reset your progress on a puzzle.",
+				ArgsUsage: "DATE",
+				Description: `Start the puzzle for DATE again from scratch. Your previous attempt
+is archived rather than deleted. DATE takes the same forms as for
+brack itself.
+
+Example:
+
+$ brack reset 2024-03-01`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "don't ask for confirmation",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("expected exactly one argument, DATE")
+					}
+					d, err := parseDateArg(cmd.Args().First())
+					if err != nil {
+						return err
+					}
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					return runReset(os.Stdout, s, d, cmd.Bool("yes"))
+				},
+			},
+			{
+				Name:      "attempts",
+				Usage:     "List your attempts at a puzzle.",
+				ArgsUsage: "DATE",
+				Description: `List your archived attempts at the puzzle for DATE, and the current
+one, with your personal bests (the fastest time and the fewest
+incorrect guesses) marked, followed by the puzzle's completion text
+once you've solved it. DATE takes the same forms as for brack itself.`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "spoilers",
+						Usage: "show the completion text even if the puzzle isn't solved",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("expected exactly one argument, DATE")
+					}
+					d, err := parseDateArg(cmd.Args().First())
+					if err != nil {
+						return err
+					}
+					s, cfg, err := openUserGames()
+					if err != nil {
+						return err
+					}
+					spoilers := cmd.Bool("spoilers")
+					if spoilers && cfg.SpoilerLock && !s.solved(d.Format(time.DateOnly)) {
+						spoilers = confirm("You haven't solved this puzzle. Show its completion text anyway?", false)
+					}
+					return runAttempts(os.Stdout, s, d, spoilers)
+				},
+			},
+			{
+				Name:      "penalties",
+				Usage:     "Show or choose how new games are scored.",
+				ArgsUsage: "[PRESET]",
+				Description: `Games are scored out of 100, less a penalty for each hint (the letter
+helper, or partial credit), each answer revealed by giving up, and each
+wrong guess. Without PRESET, show the penalties of each preset, marking
+the profile's. With PRESET (official, lenient or brutal), choose it for
+the profile's new games. Each attempt keeps the penalties it was started
+with.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					if cmd.Args().Len() > 0 {
+						return setProfilePenalties(s, cmd.Args().First())
+					}
+					return runPenalties(os.Stdout, s)
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "Show how today's puzzle is going, and your streak.",
+				Description: `Show whether today's puzzle has been played and solved, and your
+current streak, without opening the game or fetching anything. With
+--json it's written as JSON, e.g. for a status bar or shell prompt.`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "write the status as JSON",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					return runStatus(os.Stdout, s, time.Now(), cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "guess",
+				Usage:     "Guess an answer without opening the game.",
+				ArgsUsage: "DATE ANSWER",
+				Description: `Enter ANSWER into the puzzle for DATE, as if it were typed into the
+game, then show whether it was right and how the puzzle stands. It's
+saved like any other guess, so a puzzle can be played from a script,
+an editor or a chat bot, and picked up in the game at any point. DATE
+takes the same forms as for brack itself. A wrong guess isn't an
+error; with --json, check "right".
 
-			// Done!
-			return nil
+Example:
+
+$ brack guess 2024-03-01 "peanut butter"`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "write the result as JSON",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 2 {
+						return fmt.Errorf("expected exactly two arguments, DATE and ANSWER")
+					}
+					d, err := parseDateArg(cmd.Args().Get(0))
+					if err != nil {
+						return err
+					}
+					s, cfg, err := openUserGames()
+					if err != nil {
+						return err
+					}
+					return runGuess(os.Stdout, s, cfg, d, cmd.Args().Get(1), cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "report",
+				Usage:     "Summarize your games over a day, week or month.",
+				ArgsUsage: "[DATE]",
+				Description: `Summarize the games played in the day, week or month up to DATE
+(today by default; it takes the same forms as for brack itself). With
+--md the summary is written as Markdown, including the spoiler-free
+share text of each solved puzzle, for pasting into a journal or daily
+note.
+
+Example:
+
+$ brack report --period week --md`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "period",
+						Usage: "period to summarize: day, week or month",
+						Value: "week",
+					},
+					&cli.BoolFlag{
+						Name:  "md",
+						Usage: "write the report as Markdown",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					d, err := parseDateArg(cmd.Args().Get(0))
+					if err != nil {
+						return err
+					}
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					return runReport(os.Stdout, s, cmd.String("period"), d, cmd.Bool("md"))
+				},
+			},
+			{
+				Name:  "metrics",
+				Usage: "Serve your stats as Prometheus metrics.",
+				Description: `Serve your stats (streak, games played and solved, play time and so
+on) at /metrics in the Prometheus text format, for graphing in Grafana
+or similar.
+
+Example:
+
+$ brack metrics --listen :9999`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "address to serve the metrics on",
+						Value: "localhost:9999",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runMetrics(cmd.String("listen"))
+				},
+			},
+			{
+				Name:  "export",
+				Usage: "Export your games for other apps.",
+				Description: `Export your games in another format. With --ical, write an iCalendar
+(.ics) file with an all-day event for each solved puzzle, to import into
+a calendar app. With --events DATE, write the log of every guess and hint
+in each attempt at the puzzle for DATE as JSON, e.g. to chart a solve.
+With --feed FILE, write an Atom feed of your latest solves to FILE (or
+stdout, for -), to serve from a personal site; its entries have the
+spoiler-free share text of each solve. With --scrub, answers and clues
+are left out, keeping only the stats, so the export can be shared
+publicly without spoiling any puzzles.
+
+Examples:
+
+$ brack export --ical -o brack.ics
+$ brack export --events 2025-03-01 --scrub
+$ brack export --feed feed.xml`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "ical",
+						Usage: "export solved puzzles as iCalendar events",
+					},
+					&cli.StringFlag{
+						Name:  "events",
+						Usage: "export the event log of the puzzle for a date as JSON",
+					},
+					&cli.StringFlag{
+						Name:  "feed",
+						Usage: "export solved puzzles as an Atom feed, written to `FILE`",
+					},
+					&cli.BoolFlag{
+						Name:  "scrub",
+						Usage: "leave out answers and clues, to share without spoilers",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "file to write to, instead of stdout",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					events, feed := cmd.String("events"), cmd.String("feed")
+					if !cmd.Bool("ical") && events == "" && feed == "" {
+						return fmt.Errorf("choose a format to export, e.g. --ical, --events DATE or --feed FILE")
+					}
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					var d time.Time
+					if events != "" {
+						if d, err = parseDateArg(events); err != nil {
+							return err
+						}
+					}
+					write := func(w io.Writer) error {
+						switch {
+						case events != "":
+							return writeEvents(w, s, d, cmd.Bool("scrub"))
+						case feed != "":
+							return writeFeed(w, s)
+						}
+						return writeICal(w, s, time.Now(), cmd.Bool("scrub"))
+					}
+
+					path := cmd.String("output")
+					if feed != "" && path != "" {
+						return fmt.Errorf("--feed is written to its own FILE, so leave out --output")
+					} else if feed != "" {
+						path = feed
+					}
+					if path == "" || path == "-" {
+						return write(os.Stdout)
+					}
+					f, err := os.Create(path)
+					if err != nil {
+						return err
+					}
+					if err := write(f); err != nil {
+						f.Close()
+						return err
+					}
+					return f.Close()
+				},
+			},
+			{
+				Name:  "badge",
+				Usage: "Draw an SVG badge of your streak.",
+				Description: `Draw a shields.io-style SVG badge of your current streak ("Bracket
+City streak: 42"), e.g. to embed in a profile README. Set badge_path in
+the config file to have it redrawn each time you solve a puzzle.
+
+Example:
+
+$ brack badge -o streak.svg`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "file to write to, instead of stdout",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					s, err := openUserStore()
+					if err != nil {
+						return err
+					}
+					svg := streakBadge(s, time.Now())
+					if path := cmd.String("output"); path != "" {
+						return os.WriteFile(path, []byte(svg), 0o644)
+					}
+					_, err = io.WriteString(os.Stdout, svg)
+					return err
+				},
+			},
+			{
+				Name:  "site",
+				Usage: "Build a static site of your stats.",
+				Commands: []*cli.Command{
+					{
+						Name:      "build",
+						Usage:     "Build the site into a directory.",
+						ArgsUsage: "DIR",
+						Description: `Build a static HTML page of your stats into DIR/index.html: your
+streaks, a heatmap of the last year's games, and charts of your solve
+times and solves per month. It has no answers in it, so it can be
+published as is, e.g. with GitHub Pages. Only the local database is
+used.
+
+Example:
+
+$ brack site build ./out`,
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							if cmd.Args().Len() != 1 {
+								return fmt.Errorf("expected exactly one argument, DIR")
+							}
+							return runSiteBuild(os.Stdout, cmd.Args().First())
+						},
+					},
+				},
+			},
+			{
+				Name:  "trends",
+				Usage: "Chart how your solves have changed over time.",
+				Description: `Chart 90-day rolling averages of your solve time, keystrokes and
+incorrect guesses. Press m to switch between them, and w to chart the
+last 3 months, 6 months, year or all time.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return runTrends()
+				},
+			},
+			{
+				Name:  "trivia",
+				Usage: "Show the most common answers in the puzzles you've played.",
+				Description: `Show the most common answers, and the most common words in answers
+and clues, across the puzzles you've played. Only the local database is
+used.`,
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "top",
+						Usage: "how many of each to show",
+						Value: 10,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					s, cfg, err := openUserGames()
+					if err != nil {
+						return err
+					}
+					return runAnalytics(os.Stdout, s, int(cmd.Int("top")), cfg.SpoilerLock)
+				},
+			},
+			{
+				Name:  "config",
+				Usage: "Check or edit the config file.",
+				Commands: []*cli.Command{
+					{
+						Name:  "check",
+						Usage: "Check the config file for problems.",
+						Description: `Check the config file, reporting unknown keys, bad values (such as
+color or glyphs names) and input keys that clash with another
+binding, with their line numbers. Exits non-zero if there are any.`,
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							path, err := configPath()
+							if err != nil {
+								return err
+							}
+							return runConfigCheck(os.Stdout, path)
+						},
+					},
+					{
+						Name:  "edit",
+						Usage: "Open the config file in your editor.",
+						Description: `Open the config file in $VISUAL or $EDITOR (creating it if needed),
+then check it once the editor exits.`,
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							path, err := configPath()
+							if err != nil {
+								return err
+							}
+							return runConfigEdit(os.Stdout, path)
+						},
+					},
+				},
+			},
+			{
+				Name:  "db",
+				Usage: "Manage the brack database.",
+				Commands: []*cli.Command{
+					{
+						Name:      "move",
+						Usage:     "Move the database to a new location.",
+						ArgsUsage: "NEWPATH",
+						Description: `Move the database to NEWPATH (a file, or a directory to move it
+into) and update db_path in the config file to point at it.`,
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							if cmd.Args().Len() != 1 {
+								return fmt.Errorf("expected exactly one argument, NEWPATH")
+							}
+							cfg, err := loadUserConfig()
+							if err != nil {
+								return err
+							}
+							return moveStore(os.Stdout, cfg, cmd.Args().First())
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -69,6 +953,85 @@ Bracket City: https://theatlantic.com/games/bracket-city
 	}
 }
 
+// runGame plays the puzzle for the date argument, broadcasting the
+// game's state if bc isn't nil, and hosting a team session for it if
+// team isn't.
+func runGame(arg string, bc *broadcaster, team *teamHost) error {
+	// Is there a date argument?
+	d, err := parseDateArg(arg)
+	if err != nil {
+		return err
+	}
+	if err := requireTerminal(); err != nil {
+		return err
+	}
+
+	// Load the config
+	cfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	setLocale(cfg.Locale)
+
+	// Move any data from the legacy location
+	cfg, err = migrateLegacyStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Open the store of saved games
+	path, err := storePath(cfg)
+	if err != nil {
+		return err
+	}
+	s, err := openStore(path)
+	if err != nil {
+		return err
+	}
+
+	// Pick up where the last session left off, or once today's puzzle
+	// is done, start at the home screen
+	if arg == "" && bc == nil && team == nil {
+		if view, date, ok := restoredSession(s); ok && cfg.RestoreSession {
+			slog.Debug("restoring session", "view", view, "date", date.Format(time.DateOnly))
+			if view == viewCalendar {
+				return showCalendar(s, cfg, date)
+			}
+			d = date
+		} else if gs, ok := s.game(today()); ok && gs.Done {
+			return showHome(s, cfg)
+		}
+	}
+
+	// Load the puzzle data
+	puzzle, err := loadPuzzle(s, d)
+	if err != nil {
+		return err
+	}
+
+	// Detect the background color before the game starts reading input
+	setTheme(cfg.Theme)
+
+	// Run the puzzle
+	m := newModel(puzzle, s, cfg)
+	m.bc = bc
+	if team != nil {
+		team.date = puzzle.PuzzleDate
+		m.team, m.player = team, team.name
+	}
+	top, err := runViews(newSession(m), whatsNew(s)...)
+	if err != nil {
+		return err
+	}
+	if cfg.RestoreSession {
+		return saveLastView(s, top)
+	}
+
+	// Done!
+	return nil
+}
+
 func parseDateArg(s string) (time.Time, error) {
 	// If no date is provided, use the current date
 	if s == "" {
@@ -83,3 +1046,11 @@ func parseDateArg(s string) (time.Time, error) {
 	// Parse the date
 	return time.Parse("2006-01-02", s)
 }
+
+// rng is the source of randomness for any randomized features, so
+// that it can be seeded for reproducible runs.
+var rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+
+func seedRand(seed uint64) {
+	rng = rand.New(rand.NewPCG(seed, seed))
+}
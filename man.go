@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+// roffEscaper escapes text for use in a roff document.
+var roffEscaper = strings.NewReplacer(
+	`\`, `\e`,
+	`-`, `\-`,
+)
+
+// roff escapes a line of text, including control characters at the
+// start of the line.
+func roff(s string) string {
+	s = roffEscaper.Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// writeManPage writes a man page for the command (and its
+// subcommands) in roff format.
+func writeManPage(w io.Writer, cmd *cli.Command) {
+	name := cmd.Name
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n",
+		strings.ToUpper(name), name, cmd.Version)
+
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", name, roff(cmd.Usage))
+
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n[\\fIOPTIONS\\fR]", name)
+	if len(cmd.VisibleCommands()) > 0 {
+		fmt.Fprint(w, " [\\fICOMMAND\\fR]")
+	}
+	if cmd.ArgsUsage != "" {
+		fmt.Fprintf(w, " \\fI%s\\fR", roff(cmd.ArgsUsage))
+	}
+	fmt.Fprintln(w)
+
+	if cmd.Description != "" {
+		fmt.Fprintln(w, ".SH DESCRIPTION")
+		writeManText(w, cmd.Description)
+	}
+
+	if flags := cmd.VisibleFlags(); len(flags) > 0 {
+		fmt.Fprintln(w, ".SH OPTIONS")
+		writeManFlags(w, flags)
+	}
+
+	if cmds := cmd.VisibleCommands(); len(cmds) > 0 {
+		fmt.Fprintln(w, ".SH COMMANDS")
+		for _, sub := range cmds {
+			fmt.Fprintf(w, ".SS \"%s %s\"\n", name, sub.Name)
+			writeManText(w, sub.Usage)
+			if sub.Description != "" {
+				writeManText(w, sub.Description)
+			}
+			writeManFlags(w, sub.VisibleFlags())
+		}
+	}
+}
+
+// writeManText writes free text as paragraphs, keeping lines that look
+// like shell examples as-is.
+func writeManText(w io.Writer, s string) {
+	fmt.Fprintln(w, ".PP")
+	var inExample bool
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		line = strings.TrimSpace(line)
+		isExample := strings.HasPrefix(line, "$")
+		switch {
+		case isExample && !inExample:
+			fmt.Fprintln(w, ".nf")
+		case !isExample && inExample:
+			fmt.Fprintln(w, ".fi")
+		}
+		inExample = isExample
+
+		if line == "" {
+			fmt.Fprintln(w, ".PP")
+			continue
+		}
+		fmt.Fprintln(w, roff(line))
+	}
+	if inExample {
+		fmt.Fprintln(w, ".fi")
+	}
+}
+
+func writeManFlags(w io.Writer, flags []cli.Flag) {
+	for _, f := range flags {
+		var names []string
+		for _, n := range f.Names() {
+			if len(n) == 1 {
+				names = append(names, `\fB\-`+n+`\fR`)
+			} else {
+				names = append(names, `\fB\-\-`+roff(n)+`\fR`)
+			}
+		}
+
+		fmt.Fprintln(w, ".TP")
+		df, ok := f.(cli.DocGenerationFlag)
+		if !ok {
+			fmt.Fprintln(w, strings.Join(names, ", "))
+			continue
+		}
+		if df.TakesValue() {
+			fmt.Fprintf(w, "%s \\fI%s\\fR\n", strings.Join(names, ", "), df.TypeName())
+		} else {
+			fmt.Fprintln(w, strings.Join(names, ", "))
+		}
+
+		usage := df.GetUsage()
+		if v := df.GetValue(); df.TakesValue() && v != "" && v != `""` {
+			usage += " (default: " + v + ")"
+		}
+		fmt.Fprintln(w, roff(usage))
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// solvedOn reports whether the puzzle for a day was solved.
+func solvedOn(s *store, day time.Time) bool {
+	gs, ok := s.game(day.Format(time.DateOnly))
+	return ok && gs.Done && !gs.GaveUp
+}
+
+// currentStreak is how many days in a row, up to today, the puzzle was
+// solved. Today's puzzle not being solved yet doesn't break it.
+func currentStreak(s *store, today time.Time) int {
+	day := today
+	if !solvedOn(s, day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	var n int
+	for solvedOn(s, day) {
+		n++
+		day = day.AddDate(0, 0, -1)
+	}
+	return n
+}
+
+// writeMetrics writes the player's stats in the Prometheus text format.
+func writeMetrics(w io.Writer, s *store, now time.Time) {
+	var played, solved, incorrect, letters, hints int
+	var seconds int64
+	for _, gs := range s.allGames() {
+		played++
+		if gs.Done && !gs.GaveUp {
+			solved++
+		}
+		incorrect += gs.Incorrect
+		letters += gs.Chars
+		hints += gs.HintsUsed
+		seconds += gs.ElapsedSeconds
+	}
+
+	metric := func(name, kind, help string, v any) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, v)
+	}
+	metric("brack_streak_days", "gauge", "Days in a row the puzzle was solved.", currentStreak(s, now))
+	metric("brack_games_played_total", "counter", "Games played, including replays.", played)
+	metric("brack_puzzles_solved_total", "counter", "Games solved, including replays.", solved)
+	metric("brack_incorrect_guesses_total", "counter", "Incorrect guesses across all games.", incorrect)
+	metric("brack_letters_typed_total", "counter", "Letters typed across all games.", letters)
+	metric("brack_hints_used_total", "counter", "Hints used across all games.", hints)
+	metric("brack_play_time_seconds_total", "counter", "Time spent solving across all games.", seconds)
+}
+
+// runMetrics serves the player's stats for Prometheus to scrape at
+// /metrics. The database is re-read on each scrape, so it stays up to
+// date with games played in the meantime.
+func runMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		s, err := openUserStore()
+		if err != nil {
+			slog.Error("failed to open database for metrics", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, s, time.Now())
+	})
+	fmt.Printf("Serving metrics at http://%s/metrics\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// legacyDataDir is where older versions of brack kept their data.
+func legacyDataDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".brack"), nil
+}
+
+// moveFile moves a file, falling back to copying it when it can't
+// simply be renamed (e.g. across filesystems).
+func moveFile(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(to)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(to)
+		return err
+	}
+	return os.Remove(from)
+}
+
+// moveStore relocates the database to newPath (a file, or a directory
+// to put it in) and points the config at the new location.
+func moveStore(w io.Writer, cfg config, newPath string) error {
+	if newPath == "" {
+		return errors.New("expected a path to move the database to")
+	}
+	from, err := storePath(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(from); err != nil {
+		return fmt.Errorf("no database to move at %s: %w", from, err)
+	}
+
+	to, err := filepath.Abs(newPath)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(to); err == nil && fi.IsDir() || os.IsPathSeparator(newPath[len(newPath)-1]) {
+		to = filepath.Join(to, filepath.Base(from))
+	}
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+
+	// Move it, then update the config
+	if err := moveFile(from, to); err != nil {
+		return err
+	}
+	slog.Info("moved database", "from", from, "to", to)
+	cfgPath, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := setConfigString(cfgPath, "db_path", to); err != nil {
+		return fmt.Errorf("moved database to %s, but failed to update the config: %w", to, err)
+	}
+	fmt.Fprintf(w, "Moved database from %s to %s\n", from, to)
+	return nil
+}
+
+// migrateLegacyStore offers to move a database found in the legacy
+// ~/.brack location to the XDG data directory. If the user declines
+// the config is pointed at the legacy database, so they're only asked
+// once. It returns the (possibly updated) config.
+func migrateLegacyStore(cfg config) (config, error) {
+	if cfg.DBPath != "" {
+		return cfg, nil
+	}
+
+	// Is there anything to migrate?
+	legacy, err := legacyDataDir()
+	if err != nil {
+		return cfg, nil
+	}
+	from := filepath.Join(legacy, "brack.json")
+	if _, err := os.Stat(from); err != nil {
+		return cfg, nil
+	}
+	to, err := defaultStorePath()
+	if err != nil {
+		return cfg, err
+	}
+	if _, err := os.Stat(to); !errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+
+	// Only ask if someone is there to answer
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return cfg, nil
+	}
+	fmt.Printf("Found brack data in the old location, %s.\n", legacy)
+	move := confirm("Move it to "+filepath.Dir(to)+"?", true)
+
+	cfgPath, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+	if !move {
+		// Keep using the old location
+		if err := setConfigString(cfgPath, "db_path", from); err != nil {
+			return cfg, err
+		}
+		fmt.Printf("Keeping it where it is (db_path in %s).\n", cfgPath)
+		cfg.DBPath = from
+		return cfg, nil
+	}
+
+	if err := moveFile(from, to); err != nil {
+		return cfg, fmt.Errorf("failed to migrate data from %s: %w", legacy, err)
+	}
+	os.Remove(legacy) // Only removed if it's now empty
+	slog.Info("migrated legacy database", "from", from, "to", to)
+	fmt.Printf("Moved %s to %s.\n", from, to)
+	return cfg, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// migrations upgrade the store's data from one version to the next,
+// keyed by the version they upgrade to. Each runs once, in order, when
+// an older store is opened, and storeVersion is the last one's.
+var migrations = map[int]func(d *storedata){
+	// Games count the hints used. Before that, only whether the letter
+	// helper was used was kept, so that counts as one.
+	2: func(d *storedata) {
+		countHints := func(gs *gamestate) {
+			if gs.Assisted && gs.HintsUsed == 0 {
+				gs.HintsUsed = 1
+			}
+		}
+		for date, gs := range d.Games {
+			countHints(&gs)
+			d.Games[date] = gs
+		}
+		for _, attempts := range d.Attempts {
+			for i := range attempts {
+				countHints(&attempts[i])
+			}
+		}
+	},
+}
+
+// migrate brings the store's data up to the current version, saving it
+// if anything changed. A store from a newer version of brack can't be
+// used, since saving it could lose data.
+func (s *store) migrate() error {
+	from := max(s.data.Version, 1)
+	if from > storeVersion {
+		return fmt.Errorf("the database %s is from a newer version of brack (version %d, this one reads up to %d); run `brack upgrade`", s.path, from, storeVersion)
+	}
+	if s.data.Version == storeVersion {
+		return nil
+	}
+	for v := from + 1; v <= storeVersion; v++ {
+		slog.Info("migrating database", "path", s.path, "version", v)
+		migrations[v](&s.data)
+	}
+	s.data.Version = storeVersion
+	return s.write()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	for v := 2; v <= storeVersion; v++ {
+		if migrations[v] == nil {
+			t.Errorf("there's no migration to version %d", v)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brack.json")
+	// A database from before versions were kept
+	old := `{
+		"games": {
+			"2025-03-01": {"date": "2025-03-01", "assisted": true},
+			"2025-03-02": {"date": "2025-03-02"}
+		},
+		"attempts": {"2025-03-01": [{"date": "2025-03-01", "assisted": true}]}
+	}`
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs, _ := s.game("2025-03-01"); gs.HintsUsed != 1 {
+		t.Errorf("an assisted game has %d hints, want 1", gs.HintsUsed)
+	}
+	if gs, _ := s.game("2025-03-02"); gs.HintsUsed != 0 {
+		t.Errorf("an unassisted game has %d hints, want 0", gs.HintsUsed)
+	}
+	if as := s.attempts("2025-03-01"); len(as) != 1 || as[0].HintsUsed != 1 {
+		t.Errorf("the assisted attempt wasn't migrated: %+v", as)
+	}
+
+	disk, err := readStoreFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disk.Version != storeVersion {
+		t.Errorf("the database was saved at version %d, want %d", disk.Version, storeVersion)
+	}
+
+	// A database from a newer brack is left alone
+	newer := `{"version": 999}`
+	if err := os.WriteFile(path, []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openStore(path); err == nil || !strings.Contains(err.Error(), "newer version") {
+		t.Errorf("opening a newer database: %v", err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != newer {
+		t.Errorf("the newer database was rewritten: %s", b)
+	}
+}
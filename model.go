@@ -2,13 +2,17 @@ package main
 
 import (
 	"fmt"
-	"regexp"
+	"log/slog"
+	"slices"
 	"strings"
-	"unicode"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 var _ tea.Model = model{}
@@ -16,86 +20,715 @@ var _ tea.Model = model{}
 var headerStyle = lipgloss.NewStyle().
 	Bold(true)
 
-var bodyStyle = lipgloss.NewStyle().
-	Width(100)
-
 var activeStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("#0f0f0f")).
-	Background(lipgloss.Color("#e8c566"))
+	Foreground(colorInk).
+	Background(colorYellow)
+
+var noticeStyle = lipgloss.NewStyle().
+	Faint(true)
+
+var errorStyle = lipgloss.NewStyle().
+	Foreground(colorRed)
 
 type model struct {
-	done      bool
-	correct   int
-	incorrect int
-	chars     int
-	state     string
-	data      puzzledata
-	txtin     textinput.Model
-	w, h      int
-}
-
-func newModel(d puzzledata) model {
-	tin := textinput.New()
+	done       bool
+	correct    int
+	incorrect  int
+	chars      int
+	state      string
+	segs       []segment
+	body       string
+	completion string
+	data       puzzledata
+	store      *store
+	cfg        config
+	bc         *broadcaster
+	txtin      textinput.Model
+	w, h       int
+
+	// newVersion is a newer release of brack, if there is one
+	// the user hasn't dismissed.
+	newVersion string
+
+	// host is the profile a guest took over from (see toggleGuest),
+	// or nil when not playing as a guest.
+	host *guestHost
+
+	// team is the team session this game is hosting, if any, and
+	// player who's guessing: the host, or for a moment, a player who
+	// joined (see teamGuess).
+	team   *teamHost
+	player string
+
+	// shared is the shared file the game is played in, if any, instead
+	// of the store (see sharedFile).
+	shared *sharedGame
+
+	// The solve timer: the time solving so far, when it was last
+	// (re)started (zero when stopped), and which tick loop is live.
+	elapsed time.Duration
+	resumed time.Time
+	tickID  int
+
+	// paused hides the puzzle and stops the timer.
+	paused bool
+
+	// saveErr is set when progress couldn't be saved, and shown in
+	// a banner until it's dismissed or saving is retried.
+	saveErr error
+
+	// penalties are what the game is scored with, chosen when it's
+	// started (see profilePenalties).
+	penalties penalties
+
+	// gaveUp is set when the game was ended without solving it, and
+	// diff shows the solution compared to the final state. timing shows
+	// how long each clue took instead (see timingView). spoilersOK is
+	// set once showing the answers has been confirmed (see
+	// spoilerLocked).
+	gaveUp     bool
+	diff       bool
+	timing     bool
+	spoilersOK bool
+
+	// nudge is the clue suggested to a player who seems stuck, since
+	// progressAt (the solving time of the last correct answer). nudges
+	// counts how many have been offered.
+	nudge      string
+	nudges     int
+	progressAt time.Duration
+
+	// helper shows the letter helper panel. Using it marks the game
+	// as assisted. hints counts each hint taken.
+	helper   bool
+	assisted bool
+	hints    int
+
+	// lookup shows the panel for looking up the answers in a
+	// dictionary, with the selected answer and its definition.
+	lookup     bool
+	lookupIdx  int
+	definition string
+
+	// palette shows the command palette, with cmdin its input.
+	palette bool
+	cmdin   textinput.Model
+
+	// launching shows the launcher, a fuzzy finder over actions and
+	// puzzles.
+	launching bool
+	launcher  list.Model
+
+	// split shows the calendar, cal, in a pane beside the game on wide
+	// terminals, and calFocus is set when it has focus.
+	split    bool
+	cal      calendarModel
+	calFocus bool
+
+	// statusTmpl is the configured status line, if any, shown in
+	// place of the score.
+	statusTmpl []statusPart
+
+	// expert is set for a game in expert mode, where only the deepest
+	// clues can be answered.
+	expert bool
+
+	// solveTimes is the elapsed seconds at each correct answer, and
+	// ghost the same for the best earlier attempt, when replaying.
+	solveTimes []int64
+	ghost      []int64
+
+	// events logs what happened in the game (see gameEvent).
+	events []gameEvent
+
+	// partial is the last wrong guess's partial credit, if any (see
+	// config.PartialCredit).
+	partial *partialCredit
+
+	// cooldownUntil is when another guess can be made, after a wrong
+	// one in hard mode.
+	cooldownUntil time.Time
+
+	// selectMode numbers the clues, to answer one at a time: target
+	// is the clue chosen by its number, if any.
+	selectMode bool
+	target     string
+
+	// archived is set when there's no current game for the date, but
+	// there's a finished attempt in the archive, which is shown
+	// read-only until it's replayed. noting is set while editing the
+	// note on the puzzle.
+	archived bool
+	noting   bool
+
+	// lockedBy is the other brack process that has the puzzle open,
+	// if any, in which case it's read-only here (see store.lock).
+	lockedBy string
+
+	// When the database was last checked for changes made outside the
+	// game, and its modification time then (see watchStore).
+	watchedAt time.Time
+	diskMod   time.Time
+
+	// warmup is set for a warm-up puzzle, which isn't saved.
+	warmup bool
+
+	// dialog is the modal question being asked, if any.
+	dialog *dialog
+
+	// difficulty is the estimated difficulty, shown before starting.
+	difficulty difficulty
+
+	// toast is a short-lived message, cleared on the next key press.
+	toast string
+
+	// newBest is set when a replay beats the personal best.
+	newBest bool
+
+	// blurred is set while the terminal doesn't have focus, which
+	// also stops the timer.
+	blurred bool
+
+	// streamer hides anything that would spoil the puzzle for an
+	// audience: typed guesses, the solution and the completion URL.
+	streamer bool
+}
+
+// newModel creates the game model for a puzzle, resuming any progress
+// saved in the store. The store may be nil, in which case progress
+// isn't saved.
+func newModel(d puzzledata, s *store, cfg config) model {
+	tin := newInput(cfg)
 	tin.Focus()
-	return model{
-		data:  d,
-		txtin: tin,
-		state: d.InitialPuzzle,
+	m := model{
+		data:   d,
+		store:  s,
+		cfg:    cfg,
+		txtin:  tin,
+		expert: cfg.ExpertMode,
+	}
+	m.statusTmpl, _ = parseStatusLine(cfg.StatusLine)
+	m.setState(d.InitialPuzzle)
+	m.setStreamer(cfg.StreamerMode)
+	m.setSelectMode(cfg.AnswerMode == "select")
+	m.difficulty = estimateDifficulty(d, s)
+	m.penalties = profilePenalties(s)
+	if s == nil {
+		m.resumed = time.Now()
+		return m
+	}
+	m.cal = newCalendarPane(s, d.PuzzleDate)
+	if d.PuzzleDate != warmupDate && !noSave {
+		if holder, ok, err := s.lock(d.PuzzleDate); err != nil {
+			slog.Error("failed to lock the puzzle", "date", d.PuzzleDate, "err", err)
+		} else if !ok {
+			m.lockedBy = holder.String()
+		}
+	}
+	if gs, ok := s.game(d.PuzzleDate); ok {
+		slog.Debug("resuming game", "date", gs.Date, "correct", gs.Correct)
+		m.resume(gs)
+	} else if gs, ok := latestFinished(s, d.PuzzleDate); ok {
+		slog.Debug("showing archived game", "date", gs.Date)
+		m.resume(gs)
+		m.archived = true
+	}
+	if !m.done {
+		m.resumed = time.Now()
+	}
+	m.loadGhost()
+	return m
+}
+
+// resume picks up a saved game.
+func (m *model) resume(gs gamestate) {
+	m.done = gs.Done
+	m.gaveUp = gs.GaveUp
+	m.setState(gs.State)
+	m.correct = gs.Correct
+	m.incorrect = gs.Incorrect
+	m.chars = gs.Chars
+	m.nudges = gs.Nudges
+	m.assisted = gs.Assisted
+	m.hints = gs.HintsUsed
+	m.expert = gs.Expert
+	m.solveTimes = slices.Clone(gs.SolveTimes)
+	m.events = slices.Clone(gs.Events)
+	m.elapsed = time.Duration(gs.ElapsedSeconds) * time.Second
+	m.progressAt = m.elapsed
+	if gs.Penalties != nil {
+		m.penalties = *gs.Penalties
+	}
+}
+
+func (m model) gamestate() gamestate {
+	return gamestate{
+		Date:           m.data.PuzzleDate,
+		State:          m.state,
+		Correct:        m.correct,
+		Incorrect:      m.incorrect,
+		Chars:          m.chars,
+		Done:           m.done,
+		GaveUp:         m.gaveUp,
+		Nudges:         m.nudges,
+		Assisted:       m.assisted,
+		HintsUsed:      m.hints,
+		Expert:         m.expert,
+		Penalties:      &m.penalties,
+		ElapsedSeconds: int64(m.elapsedNow() / time.Second),
+		SolveTimes:     m.solveTimes,
+		Events:         m.events,
+	}
+}
+
+// replay archives the current attempt and starts the puzzle again.
+func (m model) replay() (model, tea.Cmd) {
+	if m.store != nil && !m.warmup && !m.archived {
+		if err := m.store.archiveGame(m.data.PuzzleDate); err != nil {
+			slog.Error("failed to archive game", "date", m.data.PuzzleDate, "err", err)
+			m.saveErr = err
+		}
+	}
+	slog.Debug("replaying", "date", m.data.PuzzleDate)
+	m.archived = false
+	m.penalties = profilePenalties(m.store)
+
+	m.setState(m.data.InitialPuzzle)
+	m.correct, m.incorrect, m.chars = 0, 0, 0
+	m.done = false
+	m.gaveUp, m.diff, m.timing = false, false, false
+	m.nudge, m.nudges, m.progressAt = "", 0, 0
+	m.helper, m.assisted, m.hints, m.partial = false, false, 0, nil
+	m.lookup, m.lookupIdx, m.definition = false, 0, ""
+	m.newBest = false
+	m.elapsed = 0
+	m.solveTimes, m.events = nil, nil
+	m.loadGhost()
+	m.txtin.Reset()
+	m.publish()
+	return m, m.startTimer()
+}
+
+// pause hides the puzzle and stops the timer.
+func (m *model) pause() {
+	m.paused = true
+	m.stopTimer()
+}
+
+// giveUp ends the game without solving it.
+func (m *model) giveUp() {
+	m.done, m.gaveUp = true, true
+	m.logEvent("give_up", "", "")
+	m.stopTimer()
+	m.renderBody()
+	slog.Info("gave up", "date", m.data.PuzzleDate, "correct", m.correct)
+	m.save()
+	m.publish()
+}
+
+// showInfo opens the rules and info screen.
+func (m model) showInfo() tea.Cmd {
+	return push(infoModel{store: m.store, w: m.w})
+}
+
+// confirmGiveUp asks before giving up.
+func (m *model) confirmGiveUp() {
+	m.confirmAction(tr("Give up on this puzzle?"), func(m model) (model, tea.Cmd) {
+		m.giveUp()
+		return m, nil
+	})
+}
+
+// setStreamer turns streamer mode on or off.
+func (m *model) setStreamer(on bool) {
+	m.streamer = on
+	if on {
+		// Close anything showing the answers
+		m.lookup, m.diff, m.timing = false, false, false
+		m.txtin.EchoMode = textinput.EchoPassword
+		m.txtin.EchoCharacter = '•'
+	} else {
+		m.txtin.EchoMode = textinput.EchoNormal
+	}
+}
+
+// publish sends the game's state to any spectators.
+func (m model) publish() {
+	if m.team != nil && m.data.PuzzleDate == m.team.date {
+		m.team.publish(m.teamState())
+	}
+	if m.bc != nil {
+		m.bc.publish(m.snapshot())
+	}
+}
+
+// snapshot returns the game's state as shown to spectators and to
+// scripts playing without the TUI.
+func (m model) snapshot() broadcastState {
+	return broadcastState{
+		Date:      m.data.PuzzleDate,
+		Puzzle:    m.state,
+		Correct:   m.correct,
+		Total:     len(m.data.Solutions),
+		Incorrect: m.incorrect,
+		Chars:     m.chars,
+		Done:      m.done,
+	}
+}
+
+// save writes the game's progress to the store, if there is one.
+func (m *model) save() {
+	if m.store == nil || m.warmup || m.archived || m.lockedBy != "" {
+		return
+	}
+	if m.shared != nil {
+		m.saveShared()
+		return
+	}
+	m.saveErr = m.store.saveGame(m.gamestate())
+	if m.saveErr != nil {
+		slog.Error("failed to save game", "date", m.data.PuzzleDate, "err", m.saveErr)
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	m.publish()
+	var team tea.Cmd
+	if m.team != nil {
+		team = m.team.listen()
+	}
+	if m.done && !m.countingDown() {
+		return tea.Batch(m.checkForUpdate(), team)
+	}
+	return tea.Batch(m.checkForUpdate(), m.tick(), team)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer func() { m.recoverPanic(recover()) }()
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.w, m.h = msg.Width, msg.Height
+		m.resize(msg.Width, msg.Height)
+		m.renderBody()
+		if m.launching {
+			m.launcher.SetSize(m.bodyWidth(), max(m.h-2, 10))
+		}
+
+	case teamGuessMsg:
+		n, cmd := m.teamGuess(msg)
+		return n, tea.Batch(cmd, m.team.listen())
+
+	case teamJoinMsg:
+		if msg.left {
+			m.toast = tr("👥 %s left", msg.name)
+		} else {
+			m.toast = tr("👥 %s joined", msg.name)
+		}
+		return m, m.team.listen()
+
+	case updateCheckMsg:
+		return m.handleUpdateCheck(msg), nil
+
+	case shownMsg:
+		// The tick loop stopped while another view was on top
+		if !m.running() && !m.countingDown() {
+			return m, nil
+		}
+		m.tickID++
+		return m, m.tick()
+
+	case tea.BlurMsg:
+		m.blurred = true
+		m.stopTimer()
+		return m, nil
+
+	case tea.FocusMsg:
+		// Coming back to the game is a good time to look for
+		// changes made elsewhere
+		m.blurred = false
+		m, cmd := m.watchStore()
+		if m.done || m.paused || m.running() {
+			return m, cmd
+		}
+		return m, tea.Batch(cmd, m.startTimer())
+
+	case copiedMsg:
+		m.toast = tr("Copied the %s to the clipboard", tr(msg.what))
+		return m, nil
+
+	case definitionMsg:
+		switch {
+		case msg.err != nil:
+			m.definition = tr("Couldn't look up %s: %v", msg.word, msg.err)
+		case msg.text == "":
+			m.definition = tr("No definition found for %s", msg.word)
+		default:
+			m.definition = msg.text
+		}
+		return m, nil
+
+	case list.FilterMatchesMsg:
+		if m.launching {
+			return m.updateLauncher(msg)
+		}
+		return m, nil
+
+	case puzzleLoadedMsg:
+		if msg.err != nil {
+			// Mark the day in the calendar pane, to retry from there
+			if m.cal.failed != nil && !msg.date.IsZero() {
+				m.cal.failed[msg.date.Format(time.DateOnly)] = msg.err
+			}
+			m.toast = tr("Couldn't load the puzzle: %v", msg.err)
+			return m, nil
+		}
+		if msg.puzzle.PuzzleDate != m.data.PuzzleDate && m.unsaved() {
+			m.toast = ""
+			m.askToSwitch(msg.puzzle)
+			return m, nil
+		}
+		return m.switchPuzzle(msg.puzzle, true)
+
+	case openTabMsg:
+		return m.Update(puzzleLoadedMsg(msg))
+
+	case profileSwitchedMsg:
+		if msg.err != nil {
+			m.toast = tr("Couldn't switch profiles: %v", msg.err)
+			return m, nil
+		}
+		return m.switchProfile(msg)
+
+	case webhookMsg:
+		if msg.err != nil {
+			m.toast = tr("Couldn't send the webhook: %v", msg.err)
+		}
+		return m, nil
+
+	case openedMsg:
+		if msg.err != nil {
+			m.toast = tr("Couldn't open the browser: %v", msg.err)
+		}
+		return m, nil
+
+	case cooldownDoneMsg:
+		return m, nil
+
+	case tickMsg:
+		if msg.id != m.tickID || !m.running() && !m.countingDown() {
+			return m, nil
+		}
+		m.checkStuck()
+		m, cmd := m.watchStore()
+		return m, tea.Batch(cmd, m.tick())
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		m.toast = ""
+
+		// Quit, saving progress
+		if key.Matches(msg, keys.Quit) {
+			slog.Debug("quitting", "date", m.data.PuzzleDate, "correct", m.correct)
+			m.save()
 			return m, tea.Quit
-		case "enter":
-			// Get the current input value
-			in := m.txtin.Value()
-			if in == "" {
+		}
+
+		// A dialog gets every key until it's answered
+		if m.dialog != nil {
+			return m.updateDialog(msg)
+		}
+
+		// So does the note while it's being edited
+		if m.noting {
+			return m.updateNote(msg)
+		}
+
+		// The launcher gets every key while it's open
+		if m.launching {
+			return m.updateLauncher(msg)
+		}
+		if key.Matches(msg, keys.Launcher) {
+			m.openLauncher()
+			return m, nil
+		}
+
+		// The command palette gets every key while it's open
+		if m.palette {
+			return m.updatePalette(msg)
+		}
+
+		// Resume from the pause screen
+		if m.paused {
+			if key.Matches(msg, keys.Pause, keys.Submit, keys.Close) {
+				m.paused = false
+				return m, m.startTimer()
+			}
+			return m, nil
+		}
+
+		// Retry saving, or dismiss the error
+		if m.saveErr != nil {
+			switch {
+			case key.Matches(msg, keys.Retry):
+				m.save()
+				return m, nil
+			case key.Matches(msg, keys.Dismiss):
+				m.saveErr = nil
 				return m, nil
 			}
+		}
 
-			// Reset the input
-			m.txtin.Reset()
+		// Switch between the game and the calendar beside it
+		if m.split && key.Matches(msg, keys.Focus) {
+			m.focusCalendar(!m.calFocus)
+			return m, nil
+		}
+		if m.calFocus {
+			return m.updateCalendarPane(msg)
+		}
 
-			// Is that value a correct answer?
-			for q, a := range getActiveQuestions(m.data, m.state) {
-				if !strings.EqualFold(in, a) {
-					continue
-				}
+		// Jump to today's puzzle
+		if key.Matches(msg, keys.Today) && (msg.Alt || m.done) && m.data.PuzzleDate != today() {
+			return m.runCommand("date")
+		}
+
+		// Open the command palette (while playing, only before typing
+		// an answer, since ":" could be part of one)
+		if key.Matches(msg, keys.Palette) && (m.done || m.txtin.Value() == "") {
+			m.openPalette()
+			return m, textinput.Blink
+		}
+
+		// Dismiss the update notice
+		if key.Matches(msg, keys.Dismiss) && m.newVersion != "" {
+			m.newVersion = ""
+			return m, nil
+		}
+
+		// Show the rules and info screen
+		if key.Matches(msg, keys.Info) {
+			return m, m.showInfo()
+		}
+
+		// Pause the game
+		if key.Matches(msg, keys.Pause) && !m.done {
+			m.pause()
+			return m, nil
+		}
 
-				// If we got here, the answer is correct
-				m.correct++
+		// Toggle streamer mode
+		if key.Matches(msg, keys.Streamer) {
+			m.setStreamer(!m.streamer)
+			return m, nil
+		}
 
-				// Replace the question with the correct answer
-				m.state = strings.Replace(m.state, "["+q+"]", a, 1)
+		// While another brack has the puzzle open, answers can't be
+		// submitted, but enter checks whether it's been closed
+		if m.lockedBy != "" && !m.done && key.Matches(msg, keys.Submit, keys.GiveUp, keys.Helper) {
+			return m.retryLock()
+		}
 
-				// Done?
-				if m.correct == len(m.data.Solutions) {
-					m.done = true
-					return m, tea.Quit
+		// Give up, ending the game
+		if key.Matches(msg, keys.GiveUp) && !m.done {
+			m.confirmGiveUp()
+			return m, nil
+		}
+
+		// Toggle the letter helper
+		if key.Matches(msg, keys.Helper) && !m.done {
+			m.helper = !m.helper
+			m.assisted = true
+			if m.helper {
+				m.hints++
+				m.logEvent("hint", "", "")
+			}
+			return m, nil
+		}
+
+		// Once the puzzle is solved, show the results
+		if m.done {
+			if m.lookup {
+				return m.updateLookup(msg)
+			}
+			switch {
+			case key.Matches(msg, keys.Lookup, keys.Diff, keys.Timing) && m.streamer:
+				m.toast = noticeStyle.Render(tr("(solution hidden in streamer mode)"))
+			case key.Matches(msg, keys.Lookup):
+				return m.showSpoilers(func(m model) (model, tea.Cmd) {
+					m.lookup = true
+					return m, nil
+				})
+			case key.Matches(msg, keys.Next):
+				if date, _, ok := m.nextPuzzle(); ok {
+					return m.runCommand("date " + date)
+				}
+			case key.Matches(msg, keys.PlayAgain):
+				if m.archived {
+					return m.replay()
 				}
+				m.confirmAction(tr("Start this puzzle over? This attempt will be archived."), model.replay)
+			case key.Matches(msg, keys.Note) && m.store != nil && !m.warmup:
+				m.editNote()
+				return m, textinput.Blink
+			case key.Matches(msg, keys.Diff) && m.diff:
+				m.diff = false
+			case key.Matches(msg, keys.Diff):
+				return m.showSpoilers(func(m model) (model, tea.Cmd) {
+					m.diff, m.timing = true, false
+					return m, nil
+				})
+			case key.Matches(msg, keys.Timing) && m.timing:
+				m.timing = false
+			case key.Matches(msg, keys.Timing):
+				return m.showSpoilers(func(m model) (model, tea.Cmd) {
+					m.timing, m.diff = true, false
+					return m, nil
+				})
+			case key.Matches(msg, keys.Open):
+				return m, openURL(m.data.CompletionURL)
+			case key.Matches(msg, keys.CopyURL):
+				return m, copyToClipboard("URL", m.data.CompletionURL)
+			case key.Matches(msg, keys.Share):
+				return m, copyToClipboard("share text", m.shareText())
+			case key.Matches(msg, keys.Close):
+				return m, tea.Quit
+			}
+			return m, nil
+		}
 
-				// Good.
+		// In select mode, choose a clue first
+		if m.selectMode {
+			if m.target == "" {
+				if m, cmd, ok := m.updateSelect(msg); ok {
+					return m, cmd
+				}
+			} else if key.Matches(msg, keys.Dismiss) {
+				m.target = ""
+				m.txtin.Reset()
 				return m, nil
 			}
+		}
 
-			// If we got here, the answer is incorrect
-			m.incorrect++
-			return m, nil
+		switch {
+		case key.Matches(msg, keys.Submit):
+			// Get the current input value
+			in := m.txtin.Value()
+			if in == "" || m.cooldownLeft() > 0 {
+				return m, nil
+			}
+
+			// Reset the input
+			m.txtin.Reset()
+			if m.shared != nil {
+				return m.submitShared(in)
+			}
+			return m.submit(in)
 
 		default:
-			if txt := msg.String(); len(txt) == 1 && unicode.IsLetter(rune(txt[0])) {
-				m.chars++
+			// Count letters typed (fast typing can arrive as
+			// several runes at once), but not alt+b and the like,
+			// which move the cursor
+			if msg.Type == tea.KeyRunes && !msg.Alt {
+				m.chars += countLetters(msg.Runes)
 			}
 			tin, cmd := m.txtin.Update(msg)
 			m.txtin = tin
@@ -105,64 +738,309 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// submit checks a guess against the clues, as entered into the game.
+func (m model) submit(in string) (model, tea.Cmd) {
+	m.partial = nil
+
+	// Is that value a correct answer? In select mode, only
+	// the chosen clue's answer counts.
+	qs := getActiveQuestions(m.data, m.state)
+	if m.target != "" {
+		qs = map[string]string{m.target: qs[m.target]}
+	}
+	allowed := qs
+	if m.expert {
+		allowed = innermostQuestions(m.data, m.state)
+	}
+	for q, a := range qs {
+		if !answersMatch(in, a, m.cfg.IgnoreAccents) {
+			continue
+		}
+
+		// In expert mode, a right answer out of order is
+		// turned away, but not counted as wrong
+		if _, ok := allowed[q]; !ok {
+			m.toast = errorStyle.Render(tr("🎓 Expert mode: solve the innermost clues first"))
+			return m, nil
+		}
+
+		// If we got here, the answer is correct
+		m.correct++
+		m.solveTimes = append(m.solveTimes, int64(m.elapsedNow()/time.Second))
+		m.logEvent("correct", in, q)
+		m.nudge, m.progressAt = "", m.elapsedNow()
+
+		// Replace the question with the correct answer
+		m.target = ""
+		m.setState(strings.Replace(m.state, "["+q+"]", a, 1))
+		slog.Debug("correct answer", "correct", m.correct)
+
+		// Done?
+		if m.correct == len(m.data.Solutions) {
+			m.done = true
+			m.stopTimer()
+			m.renderBody()
+			if m.store != nil {
+				pb, ok := bestOf(m.store.attempts(m.data.PuzzleDate))
+				m.newBest = ok && pb.beats(m.gamestate())
+			}
+			slog.Info("puzzle complete", "date", m.data.PuzzleDate, "incorrect", m.incorrect, "chars", m.chars)
+			m.save()
+			m.publish()
+			return m, tea.Batch(m.notifyWebhook(), m.updateBadge())
+		}
+
+		// Good.
+		m.save()
+		m.publish()
+		return m, nil
+	}
+
+	// If we got here, the answer is incorrect
+	m.incorrect++
+	m.logEvent("incorrect", in, m.target)
+	if m.cfg.PartialCredit {
+		if p, ok := findPartialCredit(in, qs, m.cfg.IgnoreAccents); ok {
+			m.partial = &p
+			m.assisted = true
+			m.hints++
+			m.logEvent("hint", in, p.clue)
+		}
+	}
+	cmd := m.startCooldown()
+	slog.Debug("incorrect answer", "incorrect", m.incorrect)
+	m.publish()
+	return m, cmd
+}
+
 func (m model) View() string {
-	var s string
-	rest := m.state
-	re := regexp.MustCompile(`\[([^\[\]]+)\]`)
+	defer func() { m.recoverPanic(recover()) }()
 
-	for re.MatchString(rest) {
-		// Get the (first) match
-		q := re.FindStringSubmatch(rest)[0]
+	v := m.screen()
 
-		// Split on the match
-		parts := re.Split(rest, 2)
-		left, right := parts[0], parts[1]
+	// Show any error above the game
+	if m.saveErr != nil && !m.paused {
+		v = lipgloss.JoinVertical(lipgloss.Left,
+			errorStyle.Width(m.bodyWidth()).Render(tr(
+				"⚠️ Couldn't save your progress: %v (%s to retry, %s to dismiss)",
+				m.saveErr, keys.Retry.Help().Key, keys.Dismiss.Help().Key,
+			)),
+			v,
+		)
+	}
 
-		// Add the left part to the string as is
-		s += left
+	if m.dialog == nil {
+		v = m.centered(v)
+	}
+	if m.split {
+		v = m.splitView(v)
+	}
 
-		// Format and add the question
-		s += activeStyle.Render(q)
+	// Dim everything while the terminal isn't focused
+	if m.blurred {
+		v = noticeStyle.Render(ansi.Strip(v))
+	}
+	return v
+}
 
-		// Set the rest of the string to the right part
-		rest = right
+// screen renders whichever screen is showing.
+func (m model) screen() string {
+	if m.dialog != nil {
+		return m.dialogView()
+	}
+	if m.launching {
+		return m.launcherView()
+	}
+	if m.paused {
+		return m.pausedView()
 	}
 
-	// Add the rest of the string as is
-	s += rest
+	s := m.body
 
 	// Format the score
 	score := fmt.Sprintf(
-		"✅ %d ❌ %d ⌨️ %d",
+		"✅ %d ❌ %d ⌨️ %d ⏱️ %s",
 		m.correct,
 		m.incorrect,
 		m.chars,
+		formatElapsed(m.elapsedNow()),
 	)
+	if m.assisted {
+		score += " " + tr("🛟 assisted")
+	}
+	if m.expert {
+		score += " " + tr("🎓 expert")
+	}
+	if m.statusTmpl != nil {
+		score = m.statusLine()
+	}
 
 	if m.done {
-		return lipgloss.JoinVertical(lipgloss.Left,
-			headerStyle.Render(
-				"[ Bracket City | "+m.data.PuzzleDate+" ]",
-			),
-			score,
-			"---",
-			bodyStyle.Width(min(m.w, 100)).Render(s),
-			"---",
-			"🎉 You win! 🎉",
-			"URL: "+m.data.CompletionURL,
+		// Don't spoil the solution for an audience
+		url := m.data.CompletionURL
+		if m.streamer {
+			s = noticeStyle.Render(tr("(solution hidden in streamer mode)"))
+			url = noticeStyle.Render(tr("(hidden in streamer mode)"))
+		}
+
+		switch {
+		case m.streamer:
+			// Nothing that shows the answers
+		case m.lookup:
+			return m.lookupView()
+		case m.diff:
+			s = m.diffView()
+		case m.timing:
+			s = m.timingView()
+		}
+
+		win := tr("🎉 You win! 🎉")
+		if m.gaveUp {
+			win = tr("🏳️ You gave up (%s to see what you missed)", keys.Diff.Help().Key)
+		}
+		if m.newBest {
+			win += "  " + tr("🏅 New personal best!")
+		}
+
+		var b strings.Builder
+		b.WriteString(headerStyle.Render("[ Bracket City | "+m.data.PuzzleDate+" ]") + "\n")
+		if m.archived {
+			b.WriteString(noticeStyle.Render(tr("📖 Your last finished attempt, read-only (%s to play again)", keys.PlayAgain.Help().Key)) + "\n")
+		}
+		b.WriteString(score + "\n")
+		b.WriteString("---\n")
+		b.WriteString(s + "\n")
+		b.WriteString("---\n")
+		b.WriteString(win + "\n")
+		b.WriteString(noticeStyle.Render(m.scoreView()) + "\n")
+		if m.store != nil {
+			if h := m.historyView(); h != "" {
+				b.WriteString(noticeStyle.Render(h) + "\n")
+			}
+			if n := m.noteView(); n != "" {
+				b.WriteString("\n" + n + "\n")
+			}
+		}
+		if t := teamView(teamPlayers(m.events)); t != "" {
+			b.WriteString("\n" + t + "\n")
+		}
+		if m.completion != "" && !m.streamer {
+			b.WriteString("\n" + m.completion + "\n\n")
+		}
+		if m.data.CompletionURL != "" {
+			b.WriteString("URL: " + url + "\n\n")
+		}
+		if date, unfinished, ok := m.nextPuzzle(); ok {
+			b.WriteString(m.suggestion(date, unfinished) + "\n\n")
+		}
+		if m.countingDown() {
+			b.WriteString(m.countdownView() + "\n\n")
+		}
+		b.WriteString(noticeStyle.Render(helpLine(keys.PlayAgain, keys.Diff, keys.Timing, keys.Lookup, keys.Note, keys.Open, keys.CopyURL, keys.Share)) + "\n")
+		help := []key.Binding{keys.Close, keys.Info, keys.Streamer}
+		if m.data.PuzzleDate != today() {
+			help = append(help, todayHelp(m.store, false))
+		}
+		b.WriteString(noticeStyle.Render(helpLine(help...)))
+		if m.palette {
+			b.WriteString("\n\n" + m.paletteView())
+		}
+		if m.toast != "" {
+			b.WriteString("\n\n" + m.toast)
+		}
+		return b.String()
+	}
+	// Until the first guess, show how big the puzzle is, if there's
+	// room
+	if m.correct == 0 && m.incorrect == 0 && !m.compact() {
+		size := tr(
+			"%d clues · %d words · ~%d min read",
+			len(m.data.Solutions),
+			m.data.wordCount(),
+			int(m.data.readingTime().Minutes()),
+		)
+		diff := tr("difficulty %s", m.difficulty)
+		if m.difficulty.HasTypical {
+			diff += " · " + tr("usually ~%.1f wrong on puzzles like this", m.difficulty.Typical)
+		}
+		score = lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.JoinHorizontal(lipgloss.Top, score, "   ", noticeStyle.Render(size)),
+			noticeStyle.Render(diff),
 		)
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		headerStyle.Render(
-			"[ Bracket City | "+
-				m.data.PuzzleDate+
-				" ]",
-		),
-		score,
-		"---",
-		bodyStyle.Width(min(m.w, 100)).Render(s),
-		"---",
-		m.txtin.View(),
-	)
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("[ Bracket City | "+m.data.PuzzleDate+" ]") + "\n")
+	b.WriteString(score + "\n")
+	if m.ghost != nil {
+		b.WriteString(m.ghostView() + "\n")
+	}
+	b.WriteString("---\n")
+	b.WriteString(s + "\n")
+	b.WriteString("---\n")
+	if m.nudge != "" {
+		b.WriteString(m.nudgeText() + "\n")
+	}
+	if m.selectMode {
+		b.WriteString(m.selectPrompt() + "\n")
+	} else {
+		b.WriteString(m.txtin.View() + "\n")
+	}
+	if m.partial != nil {
+		b.WriteString(m.partialView() + "\n")
+	}
+	if m.cooldownLeft() > 0 {
+		b.WriteString(m.cooldownView() + "\n")
+	}
+	if m.helper {
+		b.WriteString("\n" + m.helperView() + "\n\n")
+	}
+	if m.palette {
+		b.WriteString(m.paletteView())
+	} else {
+		help := []key.Binding{keys.Submit, keys.Info, keys.Pause, keys.Quit}
+		switch {
+		case m.compact():
+			help = []key.Binding{keys.Submit, keys.Quit}
+		case m.split:
+			help = append(help[:3], keys.Focus, keys.Quit)
+		}
+		if m.data.PuzzleDate != today() && !m.compact() {
+			help = append(help, todayHelp(m.store, true))
+		}
+		b.WriteString(noticeStyle.Render(helpLine(help...)))
+	}
+	if m.toast != "" {
+		b.WriteString("\n\n" + m.toast)
+	}
+	if m.streamer {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("🔒 Streamer mode is on (%s to turn it off)", keys.Streamer.Help().Key)))
+	}
+	if m.bc != nil {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("📡 Broadcasting at %s", m.bc.url())))
+	}
+	if m.host != nil {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("👤 Playing as a guest (:guest to switch back)")))
+	}
+	if m.team != nil {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("👥 Hosting a team game, join with brack join %s (%d joined)", m.team.joinAddr(), m.team.players())))
+	}
+	if m.shared != nil && m.shared.by != "" {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("🏠 Playing the shared game in %s, last saved by %s", m.shared.path, m.shared.by)))
+	} else if m.shared != nil {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("🏠 Playing the shared game in %s", m.shared.path)))
+	}
+	if m.lockedBy != "" {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("🔐 This puzzle is open in another brack (%s), so it's read-only here (%s to check again)", m.lockedBy, keys.Submit.Help().Key)))
+	}
+	if noSave {
+		b.WriteString("\n\n" + noticeStyle.Render(tr("🧪 Nothing is being saved (--no-save)")))
+	}
+	if m.newVersion != "" {
+		b.WriteString("\n\n" + noticeStyle.Render(tr(
+			"brack %s is available, run `brack upgrade` to install it (%s to dismiss)",
+			m.newVersion, keys.Dismiss.Help().Key,
+		)))
+	}
+	return b.String()
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// latestFinished returns the latest archived attempt at a date's
+// puzzle that was finished, for showing read-only when there's no
+// current game (e.g. after `brack reset`).
+func latestFinished(s *store, date string) (gamestate, bool) {
+	attempts := s.attempts(date)
+	for i := len(attempts) - 1; i >= 0; i-- {
+		if attempts[i].Done {
+			return attempts[i], true
+		}
+	}
+	return gamestate{}, false
+}
+
+// note returns the player's note on a date's puzzle.
+func (s *store) note(date string) string {
+	return s.data.Notes[date]
+}
+
+// setNote sets the player's note on a date's puzzle, deleting it if
+// it's empty, and writes the store to disk.
+func (s *store) setNote(date, note string) error {
+	if note == "" {
+		delete(s.data.Notes, date)
+	} else {
+		s.data.Notes[date] = note
+	}
+	return s.write()
+}
+
+// editNote starts editing the note on the puzzle, in the answer input
+// (which is idle once the game's over).
+func (m *model) editNote() {
+	m.noting = true
+	m.txtin.SetValue(m.store.note(m.data.PuzzleDate))
+	m.txtin.CursorEnd()
+	m.txtin.Focus()
+}
+
+// updateNote handles keys while editing the note: enter saves it and
+// esc leaves it as it was.
+func (m model) updateNote(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Submit):
+		if err := m.store.setNote(m.data.PuzzleDate, m.txtin.Value()); err != nil {
+			m.toast = errorStyle.Render(tr("Couldn't save the note: %v", err))
+		}
+	case key.Matches(msg, keys.Dismiss):
+	default:
+		var cmd tea.Cmd
+		m.txtin, cmd = m.txtin.Update(msg)
+		return m, cmd
+	}
+	m.noting = false
+	m.txtin.Reset()
+	return m, nil
+}
+
+// noteView shows the note on the puzzle, or the input for editing it.
+func (m model) noteView() string {
+	if m.noting {
+		return tr("📝 Note (enter to save, esc to cancel):") + "\n" + m.txtin.View()
+	}
+	if note := m.store.note(m.data.PuzzleDate); note != "" {
+		return tr("📝 %s", note)
+	}
+	return ""
+}
+
+// historyView sums up the earlier attempts at the puzzle, with the
+// personal bests over all of them.
+func (m model) historyView() string {
+	attempts := m.store.attempts(m.data.PuzzleDate)
+	if len(attempts) == 0 || m.archived && len(attempts) == 1 {
+		return ""
+	}
+	all := attempts
+	if !m.archived {
+		all = append(attempts[:len(attempts):len(attempts)], m.gamestate())
+	}
+	s := tr("%d attempts", len(all))
+	if pb, ok := bestOf(all); ok {
+		s += " · " + tr("fewest incorrect: %d", pb.Incorrect)
+		if pb.ElapsedSeconds > 0 {
+			s += " · " + tr("fastest: %s", formatElapsed(time.Duration(pb.ElapsedSeconds)*time.Second))
+		}
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// stuck reports whether it's been long enough since the last correct
+// answer (in solving time, so not counting pauses) to offer a nudge.
+func (m model) stuck() bool {
+	if m.cfg.NudgeAfter <= 0 || m.done {
+		return false
+	}
+	wait := time.Duration(m.cfg.NudgeAfter) * time.Minute
+	return m.elapsedNow()-m.progressAt >= wait
+}
+
+// shortestClue picks the active clue with the shortest text, which is
+// often the easiest place to start.
+func shortestClue(d puzzledata, state string) string {
+	var best string
+	for q := range getActiveQuestions(d, state) {
+		if best == "" || len(q) < len(best) || len(q) == len(best) && q < best {
+			best = q
+		}
+	}
+	return best
+}
+
+// checkStuck offers a nudge towards the shortest clue when the player
+// seems to be stuck. Nudges are counted separately from anything the
+// player asks for.
+func (m *model) checkStuck() {
+	if m.nudge != "" || !m.stuck() {
+		return
+	}
+	m.nudge = shortestClue(m.data, m.state)
+	if m.nudge != "" {
+		m.nudges++
+	}
+}
+
+// nudgeText is the nudge shown under the puzzle.
+func (m model) nudgeText() string {
+	return tr("💡 Stuck? Try the shortest clue: %s", activeStyle.Render("["+strings.TrimSpace(m.nudge)+"]"))
+}
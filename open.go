@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log/slog"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openedMsg reports the result of opening a URL in the browser.
+type openedMsg struct {
+	err error
+}
+
+// browserCommand returns the command that opens url in the default
+// browser on this platform.
+func browserCommand(url string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		return exec.Command("xdg-open", url)
+	}
+}
+
+// openURL opens url in the default browser, without waiting for the
+// browser to exit.
+func openURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := browserCommand(url)
+		if err := cmd.Start(); err != nil {
+			slog.Error("failed to open url", "url", url, "err", err)
+			return openedMsg{err: err}
+		}
+		go cmd.Wait()
+		slog.Debug("opened url", "url", url)
+		return openedMsg{}
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteCommands are the commands the palette understands, with their
+// arguments and a description, for completion and help.
+var paletteCommands = []struct {
+	name, args, desc string
+}{
+	{"date", "DATE", "play the puzzle for another date"},
+	{"tab", "DATE", "open the puzzle for another date in a new tab"},
+	{"stats", "", "show your stats"},
+	{"trends", "", "chart your solve times, keystrokes and errors over time"},
+	{"theme", "dark|light", "set the color theme"},
+	{"giveup", "", "give up"},
+	{"pause", "", "pause"},
+	{"streamer", "", "toggle streamer mode"},
+	{"select", "", "toggle answering clues by number"},
+	{"ghost", "", "toggle racing your best attempt"},
+	{"penalties", "official|lenient|brutal", "choose how new games are scored"},
+	{"guest", "", "switch to a throwaway guest profile, or back"},
+	{"info", "", "rules & info"},
+	{"quit", "", "quit"},
+}
+
+// puzzleLoadedMsg is the result of loading a puzzle to switch to, for
+// the date asked for.
+type puzzleLoadedMsg struct {
+	date   time.Time
+	puzzle puzzledata
+	err    error
+}
+
+// loadPuzzleCmd loads the puzzle for a date (from the store, or the
+// API) in the background.
+func loadPuzzleCmd(s *store, d time.Time) tea.Cmd {
+	return func() tea.Msg {
+		var p puzzledata
+		var err error
+		if s != nil {
+			p, err = loadPuzzle(s, d)
+		} else {
+			p, _, err = getPuzzleData(d)
+		}
+		return puzzleLoadedMsg{date: d, puzzle: p, err: err}
+	}
+}
+
+// openPalette opens the command palette.
+func (m *model) openPalette() {
+	in := newInput(m.cfg)
+	in.Prompt = ":"
+	in.Placeholder = tr("command, e.g. date 2024-03-01")
+	in.Focus()
+	m.cmdin = in
+	m.palette = true
+}
+
+// updatePalette handles keys while the command palette is open.
+func (m model) updatePalette(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Dismiss):
+		m.palette = false
+		return m, nil
+	case key.Matches(msg, keys.Submit):
+		m.palette = false
+		return m.runCommand(m.cmdin.Value())
+	}
+	var cmd tea.Cmd
+	m.cmdin, cmd = m.cmdin.Update(msg)
+	return m, cmd
+}
+
+// runCommand runs a palette command, e.g. "date 2024-03-01".
+func (m model) runCommand(line string) (model, tea.Cmd) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	slog.Debug("running command", "name", name, "arg", arg)
+	switch name {
+	case "":
+		return m, nil
+	case "date", "d":
+		d, err := parseDateArg(arg)
+		if err != nil {
+			m.toast = tr("Invalid date %q", arg)
+			return m, nil
+		}
+		m.toast = tr("Loading the puzzle for %s...", d.Format(time.DateOnly))
+		return m, loadPuzzleCmd(m.store, d)
+	case "tab", "t":
+		d, err := parseDateArg(arg)
+		if err != nil {
+			m.toast = tr("Invalid date %q", arg)
+			return m, nil
+		}
+		m.toast = tr("Loading the puzzle for %s...", d.Format(time.DateOnly))
+		return m, loadTabCmd(m.store, d)
+	case "stats":
+		return m, push(newStatsModel(m.store))
+	case "trends":
+		return m, push(newTrendsModel(m.store, time.Now()))
+	case "theme":
+		switch arg {
+		case "dark", "light":
+			darkBackground = arg == "dark"
+			m.renderBody()
+		default:
+			m.toast = tr("Unknown theme %q, expected dark or light", arg)
+		}
+	case "giveup":
+		if !m.done {
+			m.confirmGiveUp()
+		}
+	case "pause":
+		if !m.done {
+			m.pause()
+		}
+	case "streamer":
+		m.setStreamer(!m.streamer)
+	case "select":
+		m.setSelectMode(!m.selectMode)
+	case "penalties":
+		if m.store == nil {
+			return m, nil
+		}
+		if err := setProfilePenalties(m.store, arg); err != nil {
+			m.toast = errorStyle.Render(err.Error())
+		} else {
+			m.toast = tr("New games will be scored with the %s penalties", arg)
+		}
+	case "ghost":
+		if m.ghost != nil {
+			m.ghost = nil
+		} else {
+			m.loadGhost()
+			if m.ghost == nil {
+				m.toast = tr("There's no earlier attempt to race")
+			}
+		}
+	case "guest":
+		return m, m.toggleGuest()
+	case "info", "help":
+		return m, m.showInfo()
+	case "quit", "q":
+		m.save()
+		return m, tea.Quit
+	default:
+		m.toast = tr("Unknown command %q", name)
+	}
+	return m, nil
+}
+
+// switchPuzzle saves the current game (unless discarding its unsaved
+// progress) and starts playing another puzzle, keeping the window size
+// and any broadcast. The old puzzle's lock is given up, so other
+// bracks can play it.
+func (m model) switchPuzzle(p puzzledata, save bool) (model, tea.Cmd) {
+	n, cmd := m.openPuzzle(p, save)
+	if m.store != nil && n.data.PuzzleDate != m.data.PuzzleDate {
+		m.store.unlock(m.data.PuzzleDate)
+	}
+	return n, cmd
+}
+
+// openPuzzle is switchPuzzle for a puzzle played alongside the current
+// one (in another tab), keeping the current one's lock.
+func (m model) openPuzzle(p puzzledata, save bool) (model, tea.Cmd) {
+	if m.shared != nil && p.PuzzleDate != m.data.PuzzleDate {
+		m.toast = tr("This is a shared game, run brack to play other puzzles")
+		return m, nil
+	}
+	if save {
+		m.save()
+	}
+	n := newModel(p, m.store, m.cfg)
+	n.bc = m.bc
+	n.w, n.h = m.w, m.h
+	n.split, n.cal = m.split, m.cal
+	n.newVersion = m.newVersion
+	n.host = m.host
+	n.team, n.player = m.team, m.player
+	n.shared = m.shared
+	n.setStreamer(m.streamer)
+	n.renderBody()
+	n.publish()
+
+	// Retire the old tick loop
+	n.tickID = m.tickID + 1
+	if n.done {
+		return n, nil
+	}
+	return n, n.tick()
+}
+
+// unsaved reports whether the game has progress the store doesn't
+// have yet: guesses (or typing) since the last correct answer. The
+// time spent isn't counted.
+func (m model) unsaved() bool {
+	if m.store == nil || m.done || m.warmup {
+		return false
+	}
+	cur := m.gamestate()
+	gs, ok := m.store.game(m.data.PuzzleDate)
+	if !ok {
+		return cur.Chars > 0
+	}
+	return cur.Correct != gs.Correct || cur.Incorrect != gs.Incorrect || cur.Chars != gs.Chars
+}
+
+// askToSwitch asks what to do with the game's unsaved progress before
+// switching to another puzzle.
+func (m *model) askToSwitch(p puzzledata) {
+	m.dialog = &dialog{
+		question: tr("You have unsaved progress on %s.", m.data.PuzzleDate),
+		choices:  []string{tr("Save"), tr("Discard"), tr("Cancel")},
+		choose: func(m model, i int) (model, tea.Cmd) {
+			switch i {
+			case 0:
+				return m.switchPuzzle(p, true)
+			case 1:
+				slog.Info("discarding unsaved progress", "date", m.data.PuzzleDate)
+				return m.switchPuzzle(p, false)
+			}
+			return m, nil
+		},
+	}
+}
+
+// paletteView renders the command palette, with the commands that
+// match what's been typed so far.
+func (m model) paletteView() string {
+	var b strings.Builder
+	typed, _, _ := strings.Cut(m.cmdin.Value(), " ")
+	for _, c := range paletteCommands {
+		if !strings.HasPrefix(c.name, typed) {
+			continue
+		}
+		b.WriteString(noticeStyle.Render(fmt.Sprintf("  %-20s %s", strings.TrimSpace(c.name+" "+c.args), tr(c.desc))) + "\n")
+	}
+	b.WriteString(m.cmdin.View())
+	return b.String()
+}
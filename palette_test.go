@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Streamer mode turned on while playing stays on for other puzzles,
+// whether they replace the game or open in tabs.
+func TestSwitchPuzzleStreamer(t *testing.T) {
+	m := newTestGame(t).m.(model)
+	m.setStreamer(true)
+	other := m.data
+	other.PuzzleDate = "2025-01-02"
+
+	n, _ := m.switchPuzzle(other, false)
+	if !n.streamer {
+		t.Fatal("switching puzzles turned streamer mode off")
+	}
+
+	d := newDriver(newSession(newTestGame(t).m.(model))).size(80, 24)
+	if _, err := d.keys("ctrl+s"); err != nil {
+		t.Fatal(err)
+	}
+	d.send(openTabMsg{puzzle: other})
+	d.typ("secret")
+	if v := d.view(); strings.Contains(v, "secret") {
+		t.Errorf("a guess typed in a new tab is shown in streamer mode:\n%s", v)
+	}
+
+	// Turned off in one tab, it's off in the others too
+	if _, err := d.keys("ctrl+u", "ctrl+s", "alt+1"); err != nil {
+		t.Fatal(err)
+	}
+	if s := d.m.(session); s.cur != 0 || s.tabs[0].streamer {
+		t.Errorf("tab %d has streamer mode %v, want tab 0 without it", s.cur, s.tabs[s.cur].streamer)
+	}
+}
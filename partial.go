@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+)
+
+// partialCredit is a wrong guess that got some words of a multi-word
+// answer right.
+type partialCredit struct {
+	clue  string
+	words []string // the answer's words, with "" for each one missed
+	right int
+}
+
+// matchWords compares a guess with a multi-word answer word by word,
+// returning the answer's words with "" for each one the guess missed.
+func matchWords(guess, answer string, ignoreAccents bool) (words []string, right int) {
+	gw := strings.Fields(normalizeAnswer(guess, ignoreAccents))
+	aw := strings.Fields(answer)
+	if len(aw) < 2 {
+		return nil, 0
+	}
+	words = make([]string, len(aw))
+	for i, w := range aw {
+		if i < len(gw) && gw[i] == normalizeAnswer(w, ignoreAccents) {
+			words[i] = w
+			right++
+		}
+	}
+	return words, right
+}
+
+// findPartialCredit returns the clue (of qs, the clues the guess was
+// checked against) that a wrong guess got the most words of.
+func findPartialCredit(guess string, qs map[string]string, ignoreAccents bool) (partialCredit, bool) {
+	var best partialCredit
+	for q, a := range qs {
+		words, right := matchWords(guess, a, ignoreAccents)
+		if right > best.right || right == best.right && right > 0 && q < best.clue {
+			best = partialCredit{clue: q, words: words, right: right}
+		}
+	}
+	return best, best.right > 0
+}
+
+// partialView shows which words of an answer a guess got right, or in
+// streamer mode, only how many.
+func (m model) partialView() string {
+	p := m.partial
+	if m.streamer {
+		return tr("🧩 %d of %d words right for %s", p.right, len(p.words), activeStyle.Render("["+p.clue+"]"))
+	}
+	words := make([]string, len(p.words))
+	for i, w := range p.words {
+		words[i] = w
+		if w == "" {
+			words[i] = "___"
+		}
+	}
+	return tr("🧩 Partly right for %s: %s", activeStyle.Render("["+p.clue+"]"), strings.Join(words, " "))
+}
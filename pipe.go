@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// pipeError is written in place of a state by --pipe when a guess
+// can't be made.
+type pipeError struct {
+	Error      string `json:"error"`
+	RetryAfter int    `json:"retry_after,omitempty"` // seconds, during the hard mode cooldown
+}
+
+// runPipe plays the puzzle for a date over r and w, for an editor
+// plugin (e.g. for Neovim) to render: the game's state is written to w
+// as a line of JSON at the start, then each line read from r is a guess,
+// answered with the state after it. A blank line writes the state
+// again, e.g. after playing in the game meanwhile. It runs until r is
+// closed.
+func runPipe(r io.Reader, w io.Writer, e *engine, date string) error {
+	enc := json.NewEncoder(w)
+	g, err := e.game(date)
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(g); err != nil {
+		return err
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var v any
+		if answer := strings.TrimSpace(sc.Text()); answer == "" {
+			v, err = e.game(date)
+		} else {
+			v, err = e.guess(date, answer)
+		}
+		if err != nil {
+			pe := pipeError{Error: err.Error()}
+			var ce coolingError
+			if errors.As(err, &ce) {
+				pe.RetryAfter = int(math.Ceil(ce.left.Seconds()))
+			}
+			v = pe
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// confirmOrYes asks a yes/no question like confirm, unless yes (from a
+// --yes flag) answers it already. Without a terminal to ask on, it's an
+// error, so a script can't mistake a skipped step for a done one.
+func confirmOrYes(question string, yes bool) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return false, errors.New("not running in a terminal to confirm, pass --yes to go ahead")
+	}
+	return confirm(question, false), nil
+}
+
+// confirm asks a yes/no question on the terminal, returning def if the
+// user just presses enter. If stdin isn't a terminal, it returns def
+// without asking.
+func confirm(question string, def bool) bool {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return def
+	}
+
+	opts := "[y/N]"
+	if def {
+		opts = "[Y/n]"
+	}
+	fmt.Printf("%s %s ", question, opts)
+	ans, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(ans)) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
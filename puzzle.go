@@ -0,0 +1,134 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// clueRe matches an active clue: a bracket with no brackets inside it.
+var clueRe = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// segment is a run of puzzle text, either plain or an active clue
+// (including its brackets).
+type segment struct {
+	text string
+	clue bool
+}
+
+// parseSegments splits a puzzle state into plain text and active clues.
+func parseSegments(state string) []segment {
+	var segs []segment
+	last := 0
+	for _, loc := range clueRe.FindAllStringIndex(state, -1) {
+		if loc[0] > last {
+			segs = append(segs, segment{text: state[last:loc[0]]})
+		}
+		segs = append(segs, segment{text: state[loc[0]:loc[1]], clue: true})
+		last = loc[1]
+	}
+	if last < len(state) {
+		segs = append(segs, segment{text: state[last:]})
+	}
+	return segs
+}
+
+// maxBodyWidth is the widest the game is laid out by default (see
+// model.bodyWidth).
+const maxBodyWidth = 100
+
+// wrapSegments word-wraps a puzzle to width (if it's positive), splitting
+// clues that cross a line boundary so each line can be styled on its
+// own. Otherwise a highlight would run on into the start of the next
+// line, or an escape sequence could be split by the wrapping.
+func wrapSegments(segs []segment, width int) [][]segment {
+	// Split into words, each made of runs of plain and clue text, and
+	// remember whether each space between them is part of a clue
+	words := [][]segment{nil}
+	spaces := []bool{false}
+	for _, seg := range segs {
+		for i, w := range strings.Split(seg.text, " ") {
+			if i > 0 {
+				words = append(words, nil)
+				spaces = append(spaces, seg.clue)
+			}
+			if w != "" {
+				words[len(words)-1] = append(words[len(words)-1], segment{text: w, clue: seg.clue})
+			}
+		}
+	}
+
+	// Lay the words out into lines
+	var lines [][]segment
+	var line []segment
+	lineW := 0
+	add := func(seg segment) {
+		if n := len(line); n > 0 && line[n-1].clue == seg.clue {
+			line[n-1].text += seg.text
+		} else {
+			line = append(line, seg)
+		}
+	}
+	for i, word := range words {
+		wordW := 0
+		for _, seg := range word {
+			wordW += ansi.StringWidth(seg.text)
+		}
+		if i > 0 {
+			if width > 0 && lineW > 0 && lineW+1+wordW > width {
+				lines = append(lines, line)
+				line, lineW = nil, 0
+			} else {
+				add(segment{text: " ", clue: spaces[i]})
+				lineW++
+			}
+		}
+		for _, seg := range word {
+			add(seg)
+		}
+		lineW += wordW
+	}
+	return append(lines, line)
+}
+
+// renderSegments renders a puzzle wrapped to width, with its active
+// clues highlighted.
+func renderSegments(segs []segment, width int) string {
+	var b strings.Builder
+	for i, line := range wrapSegments(segs, width) {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		for _, seg := range line {
+			if seg.clue {
+				b.WriteString(activeStyle.Render(seg.text))
+			} else {
+				b.WriteString(seg.text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// setState updates the puzzle state. The puzzle is only parsed and
+// rendered when the state (or the window size) changes, rather than on
+// every frame.
+func (m *model) setState(state string) {
+	m.state = state
+	m.segs = parseSegments(state)
+	m.renderBody()
+}
+
+// renderBody re-renders the puzzle text (and, once it's solved, the
+// completion text) for the window width.
+func (m *model) renderBody() {
+	segs := m.segs
+	if m.selectMode && !m.done {
+		segs = numberClues(segs)
+	}
+	m.body = renderSegments(segs, m.bodyWidth())
+	if m.done {
+		m.completion = renderCompletionText(m.data.CompletionText, m.bodyWidth())
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// reportPeriod returns the first and last dates covered by a report
+// for the period ("day", "week" or "month") ending on d.
+func reportPeriod(period string, d time.Time) (time.Time, error) {
+	switch period {
+	case "day":
+		return d, nil
+	case "week":
+		return d.AddDate(0, 0, -6), nil
+	case "month":
+		return d.AddDate(0, -1, 1), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown period %q, expected day, week or month", period)
+}
+
+// runReport summarizes the games played in the period ending on d, as
+// plain text or as Markdown (e.g. for a daily note), including the
+// spoiler-free share text of each solved game.
+func runReport(w io.Writer, s *store, period string, d time.Time, md bool) error {
+	from, err := reportPeriod(period, d)
+	if err != nil {
+		return err
+	}
+
+	// Collect the games in the period
+	var games []gamestate
+	var solved int
+	var total time.Duration
+	for day := from; !day.After(d); day = day.AddDate(0, 0, 1) {
+		gs, ok := s.game(day.Format(time.DateOnly))
+		if !ok {
+			continue
+		}
+		games = append(games, gs)
+		if gs.Done && !gs.GaveUp {
+			solved++
+		}
+		total += time.Duration(gs.ElapsedSeconds) * time.Second
+	}
+
+	title := fmt.Sprintf("Bracket City: %s to %s", from.Format(time.DateOnly), d.Format(time.DateOnly))
+	if period == "day" {
+		title = "Bracket City: " + d.Format(time.DateOnly)
+	}
+	summary := fmt.Sprintf("%d played, %d solved, %s playing", len(games), solved, formatElapsed(total))
+
+	if !md {
+		fmt.Fprintln(w, title)
+		fmt.Fprintln(w, summary)
+		if len(games) == 0 {
+			return nil
+		}
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "DATE\tSTATUS\tTIME\tINCORRECT\tLETTERS")
+		for _, gs := range games {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", gs.Date, gameStatus(gs), formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second), gs.Incorrect, gs.Chars)
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintf(w, "## %s\n\n", title)
+	fmt.Fprintf(w, "%s.\n", summary)
+	if len(games) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Date | Status | Time | ❌ | ⌨️ |")
+	fmt.Fprintln(w, "| --- | --- | --- | --: | --: |")
+	for _, gs := range games {
+		fmt.Fprintf(w, "| %s | %s | %s | %d | %d |\n", gs.Date, gameStatus(gs), formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second), gs.Incorrect, gs.Chars)
+	}
+	for _, gs := range games {
+		if !gs.Done || gs.GaveUp {
+			continue
+		}
+		var url string
+		if p, ok := s.puzzle(gs.Date); ok {
+			url = p.CompletionURL
+		}
+		fmt.Fprintf(w, "\n```\n%s\n```\n", strings.TrimSpace(shareText(gs, url)))
+	}
+	return nil
+}
+
+// gameStatus describes how far a game got.
+func gameStatus(gs gamestate) string {
+	switch {
+	case gs.GaveUp:
+		return "gave up"
+	case gs.Done:
+		return "solved"
+	}
+	return "in progress"
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/urfave/cli/v3"
+)
+
+// overrides are the settings that can also be set with a BRACK_*
+// environment variable or a command line flag. Flags take precedence
+// over the environment, which takes precedence over the config file,
+// which takes precedence over the defaults.
+var overrides = []struct {
+	key, env, flag string
+	field          func(*config) *string
+}{
+	{"theme", "BRACK_THEME", "theme", func(c *config) *string { return &c.Theme }},
+	{"endpoint", "BRACK_ENDPOINT", "endpoint", func(c *config) *string { return &c.Endpoint }},
+	{"db_path", "BRACK_DB_PATH", "db", func(c *config) *string { return &c.DBPath }},
+	{"profile", "BRACK_PROFILE", "profile", func(c *config) *string { return &c.Profile }},
+}
+
+// flagOverrides holds the overrides given as flags, by config key. It's
+// filled in before the command runs (see setFlagOverrides).
+var flagOverrides = map[string]string{}
+
+// overrideFlags returns the global flags for the overrides.
+func overrideFlags() []cli.Flag {
+	usage := map[string]string{
+		"theme":    "color theme: auto, dark or light",
+		"endpoint": "URL of the puzzle API",
+		"db":       "path of the database",
+		"profile":  "profile to play as, with its own database",
+	}
+	var fs []cli.Flag
+	for _, o := range overrides {
+		fs = append(fs, &cli.StringFlag{
+			Name:  o.flag,
+			Usage: fmt.Sprintf("%s (or $%s)", usage[o.flag], o.env),
+		})
+	}
+	return fs
+}
+
+// setFlagOverrides records the override flags that were given.
+func setFlagOverrides(cmd *cli.Command) {
+	for _, o := range overrides {
+		if cmd.IsSet(o.flag) {
+			flagOverrides[o.key] = cmd.String(o.flag)
+		}
+	}
+}
+
+// resolveConfig applies the environment and flag overrides over a
+// config loaded from the file, checking the values they set.
+func resolveConfig(cfg config) (config, error) {
+	for _, o := range overrides {
+		// An empty variable is ignored, but an empty flag (e.g.
+		// --profile "") resets the setting
+		source := "$" + o.env
+		v := os.Getenv(o.env)
+		set := v != ""
+		if f, ok := flagOverrides[o.key]; ok {
+			source, v, set = "--"+o.flag, f, true
+		}
+		if !set {
+			continue
+		}
+		*o.field(&cfg) = v
+
+		var err error
+		switch o.key {
+		case "theme":
+			err = checkTheme(v)
+		case "profile":
+			err = checkProfile(v)
+		}
+		if err != nil {
+			return cfg, fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	return cfg, nil
+}
+
+// profileName is what a profile can be called, as it's used in a file
+// name.
+var profileName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// checkProfile returns an error for a profile name that can't be used.
+func checkProfile(name string) error {
+	if name != "" && !profileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, - and _)", name)
+	}
+	return nil
+}
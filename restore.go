@@ -0,0 +1,61 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Metadata keys for restoring the last session: the view that was
+// open, and the date it showed.
+const (
+	metaSessionView = "session.view"
+	metaSessionDate = "session.date"
+)
+
+// The views a session can be restored to.
+const (
+	viewGame     = "game"
+	viewCalendar = "calendar"
+)
+
+// saveSession records the view and date open on quitting, for
+// restoring on the next launch.
+func saveSession(s *store, view, date string) error {
+	if err := s.setMeta(metaSessionView, view); err != nil {
+		return err
+	}
+	return s.setMeta(metaSessionDate, date)
+}
+
+// saveLastView records the view that was on top on quitting.
+func saveLastView(s *store, top tea.Model) error {
+	switch v := top.(type) {
+	case calendarModel:
+		return saveSession(s, viewCalendar, v.sel.Format(time.DateOnly))
+	case session:
+		return saveSession(s, viewGame, v.tabs[v.cur].data.PuzzleDate)
+	}
+	return nil
+}
+
+// restoredSession returns the view and date to open on launch. A
+// finished game isn't restored, so that launching plays today's
+// puzzle once the last one is done.
+func restoredSession(s *store) (view string, date time.Time, ok bool) {
+	view = s.meta(metaSessionView)
+	date, err := time.ParseInLocation(time.DateOnly, s.meta(metaSessionDate), time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	switch view {
+	case viewCalendar:
+		return view, date, true
+	case viewGame:
+		if gs, played := s.game(date.Format(time.DateOnly)); played && gs.Done {
+			return "", time.Time{}, false
+		}
+		return view, date, true
+	}
+	return "", time.Time{}, false
+}
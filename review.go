@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// card is a clue from a solved puzzle, reviewed as a flashcard on an
+// SM-2 schedule.
+type card struct {
+	Clue   string `json:"clue"`
+	Answer string `json:"answer"`
+
+	// The SM-2 state: the ease factor, the days until the next review,
+	// how many reviews in a row were recalled, and when it's next due.
+	Ease     float64 `json:"ease"`
+	Interval int     `json:"interval"`
+	Reps     int     `json:"reps"`
+	Due      string  `json:"due"`
+
+	// Reviews and Lapses count the reviews and how many were missed.
+	Reviews int `json:"reviews"`
+	Lapses  int `json:"lapses"`
+}
+
+// newCardsPerReview is how many cards not seen before are added to a
+// review session.
+const newCardsPerReview = 10
+
+// newCard creates a card for a clue, due now.
+func newCard(clue, answer string) card {
+	return card{Clue: clue, Answer: answer, Ease: 2.5}
+}
+
+// grade schedules the card's next review after an answer of the given
+// quality, from 0 (blank) to 5 (perfect), following SM-2.
+func (c card) grade(q int, now time.Time) card {
+	c.Reviews++
+	if q >= 3 {
+		switch c.Reps {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Reps++
+	} else {
+		c.Lapses++
+		c.Reps = 0
+		c.Interval = 1
+	}
+	c.Ease = max(c.Ease+0.1-float64(5-q)*(0.08+float64(5-q)*0.02), 1.3)
+	c.Due = now.AddDate(0, 0, c.Interval).Format(time.DateOnly)
+	return c
+}
+
+// reviewQueue returns the cards due for review today, then up to
+// newCardsPerReview clues not seen before.
+func reviewQueue(s *store, now time.Time) []card {
+	today := now.Format(time.DateOnly)
+	var due []card
+	for _, c := range s.cards() {
+		if c.Due <= today {
+			due = append(due, c)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Due < due[j].Due })
+
+	added := 0
+	for _, c := range solvedClues(s) {
+		if added == newCardsPerReview {
+			break
+		}
+		if _, ok := s.card(c[0]); ok {
+			continue
+		}
+		due = append(due, newCard(c[0], c[1]))
+		added++
+	}
+	return due
+}
+
+// quitReview is the help for quitting a review, which is saved as it
+// goes.
+var quitReview = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit"))
+
+// reviewModel quizzes the player on clues from puzzles they've solved.
+type reviewModel struct {
+	store *store
+	cfg   config
+	queue []card
+	idx   int
+	txtin textinput.Model
+
+	// revealed is set once the current card's been answered, with
+	// recalled saying whether it was right.
+	revealed bool
+	recalled bool
+
+	// The session's stats.
+	reviewed, correct int
+	err               error
+}
+
+func newReviewModel(s *store, cfg config) reviewModel {
+	tin := newInput(cfg)
+	tin.Focus()
+	return reviewModel{store: s, cfg: cfg, queue: reviewQueue(s, time.Now()), txtin: tin}
+}
+
+func (m reviewModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	return m.updateKey(km)
+}
+
+// updateKey handles a key press.
+func (m reviewModel) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Quit), key.Matches(msg, keys.Dismiss):
+		return m, tea.Quit
+	case m.idx == len(m.queue):
+		if key.Matches(msg, keys.Close, keys.Submit) {
+			return m, tea.Quit
+		}
+		return m, nil
+	case key.Matches(msg, keys.Submit) && m.revealed:
+		m.idx++
+		m.revealed = false
+		return m, nil
+	case key.Matches(msg, keys.Submit):
+		// A blank answer counts as not knowing it
+		c := m.queue[m.idx]
+		guess := m.txtin.Value()
+		m.recalled = guess != "" && answersMatch(guess, c.Answer, m.cfg.IgnoreAccents)
+		q := 1
+		if m.recalled {
+			q = 4
+			m.correct++
+		} else if guess == "" {
+			q = 0
+		}
+		c = c.grade(q, time.Now())
+		m.queue[m.idx] = c
+		if err := m.store.saveCard(c); err != nil {
+			m.err = err
+		}
+		m.reviewed++
+		m.revealed = true
+		m.txtin.Reset()
+		return m, nil
+	case m.revealed:
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.txtin, cmd = m.txtin.Update(msg)
+	return m, cmd
+}
+
+func (m reviewModel) View() string {
+	var b strings.Builder
+	if m.idx == len(m.queue) {
+		b.WriteString(headerStyle.Render("[ Bracket City | "+tr("Review")+" ]") + "\n\n")
+		if len(m.queue) == 0 {
+			b.WriteString(tr("Nothing to review today. Solve some puzzles, or come back tomorrow.") + "\n")
+		} else {
+			b.WriteString(m.statsView())
+		}
+		b.WriteString("\n" + noticeStyle.Render(helpLine(keys.Close)))
+		return b.String()
+	}
+
+	c := m.queue[m.idx]
+	b.WriteString(headerStyle.Render(fmt.Sprintf("[ Bracket City | %s %d/%d ]", tr("Review"), m.idx+1, len(m.queue))) + "\n")
+	b.WriteString(fmt.Sprintf("✅ %d ❌ %d\n", m.correct, m.reviewed-m.correct))
+	b.WriteString("---\n")
+	b.WriteString(activeStyle.Render("["+c.Clue+"]") + "\n")
+	b.WriteString("---\n")
+	if !m.revealed {
+		b.WriteString(m.txtin.View() + "\n")
+		answer := keys.Submit
+		answer.SetHelp("enter", "answer (blank if you don't know)")
+		b.WriteString(noticeStyle.Render(helpLine(answer, quitReview)))
+		return b.String()
+	}
+
+	if m.recalled {
+		b.WriteString(tr("✅ Correct!"))
+	} else {
+		b.WriteString(tr("❌ The answer was %s", c.Answer))
+	}
+	next := tr("Next review in %d days", c.Interval)
+	if c.Interval == 1 {
+		next = tr("Next review tomorrow")
+	}
+	b.WriteString("  " + noticeStyle.Render(next) + "\n")
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(tr("⚠️ Couldn't save your progress: %v", m.err)) + "\n")
+	}
+	cont := keys.Submit
+	cont.SetHelp("enter", "next")
+	b.WriteString(noticeStyle.Render(helpLine(cont, quitReview)))
+	return b.String()
+}
+
+// statsView shows the review stats: this session's, and for all the
+// cards so far.
+func (m reviewModel) statsView() string {
+	var b strings.Builder
+	row := func(label string, v any) {
+		b.WriteString(fmt.Sprintf("%-24s %v\n", tr(label), v))
+	}
+	row("Reviewed", m.reviewed)
+	row("Recalled", m.correct)
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+	var learned, dueTomorrow, reviews, lapses int
+	for _, c := range m.store.cards() {
+		if c.Reps > 0 {
+			learned++
+		}
+		if c.Due <= tomorrow {
+			dueTomorrow++
+		}
+		reviews += c.Reviews
+		lapses += c.Lapses
+	}
+	row("Cards learned", learned)
+	row("Due tomorrow", dueTomorrow)
+	if reviews > 0 {
+		row("Recall rate", fmt.Sprintf("%.0f%%", 100*float64(reviews-lapses)/float64(reviews)))
+	}
+	return b.String()
+}
+
+// runReview reviews the clues due today.
+func runReview() error {
+	cfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	setLocale(cfg.Locale)
+	s, err := openUserStore()
+	if err != nil {
+		return err
+	}
+	_, err = runViews(newReviewModel(s, cfg))
+	return err
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGrade(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local)
+	c := newCard("the capital of France", "paris")
+
+	// Recalled perfectly three times: 1 day, then 6, then 6 × the ease
+	for _, want := range []struct {
+		interval int
+		ease     float64
+		due      string
+	}{
+		{1, 2.6, "2025-03-02"},
+		{6, 2.7, "2025-03-07"},
+		{16, 2.8, "2025-03-17"},
+	} {
+		c = c.grade(5, now)
+		if c.Interval != want.interval || math.Abs(c.Ease-want.ease) > 1e-9 || c.Due != want.due {
+			t.Fatalf("after a perfect answer: interval %d, ease %.2f, due %s; want %d, %.2f, %s",
+				c.Interval, c.Ease, c.Due, want.interval, want.ease, want.due)
+		}
+	}
+	if c.Reps != 3 || c.Reviews != 3 || c.Lapses != 0 {
+		t.Errorf("reps %d, reviews %d, lapses %d; want 3, 3, 0", c.Reps, c.Reviews, c.Lapses)
+	}
+
+	// Missing it starts over, due tomorrow, and lowers the ease
+	c = c.grade(0, now)
+	if c.Interval != 1 || c.Reps != 0 || c.Lapses != 1 || c.Due != "2025-03-02" {
+		t.Errorf("after a blank: %+v", c)
+	}
+	if math.Abs(c.Ease-2.0) > 1e-9 {
+		t.Errorf("ease after a blank is %.2f, want 2.00", c.Ease)
+	}
+
+	// The ease never drops below 1.3
+	for range 5 {
+		c = c.grade(0, now)
+	}
+	if c.Ease != 1.3 {
+		t.Errorf("ease after many blanks is %.2f, want 1.30", c.Ease)
+	}
+}
+
+func TestReviewQueue(t *testing.T) {
+	s, err := openStore(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	for _, c := range []card{
+		{Clue: "later", Answer: "a", Ease: 2.5, Due: "2025-03-11"},
+		{Clue: "today", Answer: "b", Ease: 2.5, Due: "2025-03-10"},
+		{Clue: "overdue", Answer: "c", Ease: 2.5, Due: "2025-03-01"},
+	} {
+		s.data.Cards[c.Clue] = c
+	}
+
+	var clues []string
+	for _, c := range reviewQueue(s, now) {
+		clues = append(clues, c.Clue)
+	}
+	if len(clues) != 2 || clues[0] != "overdue" || clues[1] != "today" {
+		t.Errorf("the queue is %q, want the overdue card then today's", clues)
+	}
+}
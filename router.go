@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+var _ tea.Model = router{}
+
+// pushMsg opens a view over the current one.
+type pushMsg struct {
+	view tea.Model
+}
+
+// popMsg closes the current view, going back to the one under it.
+type popMsg struct{}
+
+// shownMsg tells a view it's on top again, after the view over it was
+// closed.
+type shownMsg struct{}
+
+// push returns a command opening a view over the current one.
+func push(v tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return pushMsg{view: v}
+	}
+}
+
+// popView is a command closing the current view.
+func popView() tea.Msg {
+	return popMsg{}
+}
+
+// router runs a stack of views, such as the calendar, the game over it,
+// and the stats over that. Only the view on top gets messages (and is
+// drawn), so views can't see each other's keys or ticks.
+type router struct {
+	stack []tea.Model
+	w, h  int
+}
+
+func newRouter(v tea.Model, over ...tea.Model) router {
+	return router{stack: append([]tea.Model{v}, over...)}
+}
+
+// top returns the view on top of the stack.
+func (r router) top() tea.Model {
+	return r.stack[len(r.stack)-1]
+}
+
+// Init starts every view on the stack, though only the one on top gets
+// their messages.
+func (r router) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, v := range r.stack {
+		cmds = append(cmds, v.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (r router) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.w, r.h = msg.Width, msg.Height
+
+	// Every view keeps track of focus, e.g. to stop the game's timer,
+	// and the game hears from its team even under another view
+	case tea.FocusMsg, tea.BlurMsg, teamGuessMsg, teamJoinMsg:
+		var cmds []tea.Cmd
+		for i, v := range r.stack {
+			var cmd tea.Cmd
+			r.stack[i], cmd = v.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return r, tea.Batch(cmds...)
+
+	case pushMsg:
+		r.stack = append(r.stack, msg.view)
+		return r, tea.Batch(msg.view.Init(), r.resize())
+
+	case popMsg:
+		if len(r.stack) == 1 {
+			return r, tea.Quit
+		}
+		r.stack = r.stack[:len(r.stack)-1]
+		cmd := r.resize()
+		v, shown := r.top().Update(shownMsg{})
+		r.stack[len(r.stack)-1] = v
+		return r, tea.Batch(cmd, shown)
+	}
+
+	v, cmd := r.top().Update(msg)
+	r.stack[len(r.stack)-1] = v
+	return r, cmd
+}
+
+// resize sends the window size to the view on top, which may not have
+// seen the latest one.
+func (r *router) resize() tea.Cmd {
+	if r.w == 0 {
+		return nil
+	}
+	v, cmd := r.top().Update(tea.WindowSizeMsg{Width: r.w, Height: r.h})
+	r.stack[len(r.stack)-1] = v
+	return cmd
+}
+
+func (r router) View() string {
+	return withGlyphs(r.top().View())
+}
+
+// requireTerminal returns an error unless brack is running in a
+// terminal, which the views need to draw in and read keys from.
+func requireTerminal() error {
+	if !term.IsTerminal(os.Stdin.Fd()) || !term.IsTerminal(os.Stdout.Fd()) {
+		return errors.New("brack needs a terminal to play in (in scripts, try brack status, brack report or brack export)")
+	}
+	return nil
+}
+
+// runViews runs the program with v as its first view (and any views
+// opened over it), returning the view on top when it quits.
+func runViews(v tea.Model, over ...tea.Model) (tea.Model, error) {
+	if err := requireTerminal(); err != nil {
+		return nil, err
+	}
+	p := tea.NewProgram(newRouter(v, over...), tea.WithAltScreen(), tea.WithReportFocus())
+	res, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	if crashReport != "" {
+		return nil, fmt.Errorf("brack crashed, sorry! Your progress was saved and a crash report was written to %s", crashReport)
+	}
+	r, ok := res.(router)
+	if !ok {
+		return nil, fmt.Errorf("brack ended on an unexpected screen (%T)", res)
+	}
+	return r.top(), nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+)
+
+// rpcProtocol is the version of the stdio protocol, bumped on changes
+// a front end would have to know about.
+const rpcProtocol = 1
+
+// The stdio protocol is JSON-RPC 2.0, one message per line, so another
+// front end (a GUI, an editor plugin) can run brack rpc and drive the
+// engine with it. Batches aren't supported. The methods are:
+//
+//	version                   {"brack": "0.0.3", "protocol": 1}
+//	puzzle  {date}            the puzzle, without its answers
+//	game    {date}            the game's state
+//	guess   {date, answer}    make a guess
+//
+// Besides the standard error codes, errors from the game have these
+// codes.
+const (
+	rpcErrLocked   = 1 // the puzzle is open in another brack
+	rpcErrFinished = 2 // the puzzle has been solved or given up
+	rpcErrCooling  = 3 // wait for the cooldown; data is {"retry_after": seconds}
+	rpcErrInternal = -32000
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// runRPC answers the requests read from r, one per line, on w until r
+// is closed. Notifications (requests without an id) aren't answered.
+func runRPC(r io.Reader, w io.Writer, e *engine) error {
+	enc := json.NewEncoder(w)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		var res rpcResponse
+		switch {
+		case !json.Valid(line):
+			res.Error = &rpcError{Code: -32700, Message: "parse error"}
+		case json.Unmarshal(line, &req) != nil || req.JSONRPC != "2.0" || req.Method == "":
+			res.Error = &rpcError{Code: -32600, Message: "invalid request"}
+		default:
+			if req.ID == nil {
+				e.call(req.Method, req.Params)
+				continue
+			}
+			res.Result, res.Error = e.call(req.Method, req.Params)
+		}
+
+		res.JSONRPC, res.ID = "2.0", req.ID
+		if res.ID == nil {
+			res.ID = json.RawMessage("null")
+		}
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// call runs a method of the stdio protocol.
+func (e *engine) call(method string, raw json.RawMessage) (any, *rpcError) {
+	var params struct {
+		Date   string `json:"date"`
+		Answer string `json:"answer"`
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, &rpcError{Code: -32602, Message: "invalid params, expected {date, answer}"}
+		}
+	}
+
+	var res any
+	var err error
+	switch method {
+	case "version":
+		res = map[string]any{"brack": version, "protocol": rpcProtocol}
+	case "puzzle":
+		res, err = e.puzzle(params.Date)
+	case "game":
+		res, err = e.game(params.Date)
+	case "guess":
+		res, err = e.guess(params.Date, params.Answer)
+	default:
+		return nil, &rpcError{Code: -32601, Message: "method not found: " + method}
+	}
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	return res, nil
+}
+
+// toRPCError gives an error from the engine its code.
+func toRPCError(err error) *rpcError {
+	re := &rpcError{Code: rpcErrInternal, Message: err.Error()}
+	var ce coolingError
+	switch {
+	case errors.Is(err, errInvalidDate), errors.Is(err, errEmptyAnswer):
+		re.Code = -32602
+	case errors.Is(err, errPuzzleLocked):
+		re.Code = rpcErrLocked
+	case errors.Is(err, errPuzzleFinished):
+		re.Code = rpcErrFinished
+	case errors.As(err, &ce):
+		re.Code = rpcErrCooling
+		re.Data = map[string]int{"retry_after": int(math.Ceil(ce.left.Seconds()))}
+	}
+	return re
+}
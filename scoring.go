@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// metaPenalties is the metadata key of the profile's penalty preset.
+const metaPenalties = "score.penalties"
+
+// penalties are the points taken off a game's score of 100: for each
+// hint (the letter helper, or partial credit), for each answer revealed
+// by giving up, and for each wrong guess. Each attempt records the
+// penalties it was played with, so changing them later doesn't rescore
+// it.
+type penalties struct {
+	Preset string `json:"preset"`
+	Hint   int    `json:"hint"`
+	Reveal int    `json:"reveal"`
+	Wrong  int    `json:"wrong"`
+}
+
+// penaltyPresets are the penalties a profile can choose from. The
+// default, official, is close to the website's scoring.
+var penaltyPresets = []penalties{
+	{Preset: "official", Hint: 5, Reveal: 15, Wrong: 2},
+	{Preset: "lenient", Hint: 2, Reveal: 10, Wrong: 1},
+	{Preset: "brutal", Hint: 10, Reveal: 25, Wrong: 5},
+}
+
+// presetNames lists the presets, for messages.
+func presetNames() string {
+	var names []string
+	for _, p := range penaltyPresets {
+		names = append(names, p.Preset)
+	}
+	return strings.Join(names, ", ")
+}
+
+// penaltyPreset looks up a preset by name.
+func penaltyPreset(name string) (penalties, bool) {
+	i := slices.IndexFunc(penaltyPresets, func(p penalties) bool { return p.Preset == name })
+	if i < 0 {
+		return penalties{}, false
+	}
+	return penaltyPresets[i], true
+}
+
+// profilePenalties returns the penalties the profile plays new games
+// with, official unless it's chosen another preset.
+func profilePenalties(s *store) penalties {
+	if s != nil {
+		if p, ok := penaltyPreset(s.meta(metaPenalties)); ok {
+			return p
+		}
+	}
+	return penaltyPresets[0]
+}
+
+// setProfilePenalties chooses the preset the profile plays new games
+// with.
+func setProfilePenalties(s *store, name string) error {
+	if _, ok := penaltyPreset(name); !ok {
+		return fmt.Errorf("unknown penalties %q, expected one of %s", name, presetNames())
+	}
+	return s.setMeta(metaPenalties, name)
+}
+
+// score is a game's score out of 100, given how many clues the puzzle
+// has. Games from before scoring was added are scored with p.
+func score(gs gamestate, clues int, p penalties) int {
+	if gs.Penalties != nil {
+		p = *gs.Penalties
+	}
+	var revealed int
+	if gs.GaveUp {
+		revealed = max(clues-gs.Correct, 0)
+	}
+	return max(100-p.Hint*gs.HintsUsed-p.Reveal*revealed-p.Wrong*gs.Incorrect, 0)
+}
+
+// scoreView is the game's score, for the results screen.
+func (m model) scoreView() string {
+	p := m.penalties
+	return tr("Score: %d/100 (%s penalties: hint -%d, reveal -%d, wrong guess -%d)",
+		score(m.gamestate(), len(m.data.Solutions), p), p.Preset, p.Hint, p.Reveal, p.Wrong)
+}
+
+// runPenalties lists the presets, marking the one the profile uses.
+func runPenalties(w io.Writer, s *store) error {
+	cur := profilePenalties(s).Preset
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tPRESET\tHINT\tREVEAL\tWRONG GUESS")
+	for _, p := range penaltyPresets {
+		mark := ""
+		if p.Preset == cur {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t-%d\t-%d\t-%d\n", mark, p.Preset, p.Hint, p.Reveal, p.Wrong)
+	}
+	return tw.Flush()
+}
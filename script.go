@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyTypes maps key names (as reported by tea.KeyMsg.String) back
+// to their key types, so scripts can refer to keys like "enter",
+// "ctrl+c" or "backspace" by name.
+var keyTypes = func() map[string]tea.KeyType {
+	kts := make(map[string]tea.KeyType)
+	for k := tea.KeyType(-128); k < 128; k++ {
+		if s := k.String(); s != "" && k != tea.KeyRunes {
+			kts[s] = k
+		}
+	}
+	kts["space"] = tea.KeySpace
+	return kts
+}()
+
+// parseKey converts a single key name into a key message.
+//
+// Named keys ("enter", "ctrl+c", "alt+b") are looked up by name,
+// anything else is treated as a single typed character.
+func parseKey(s string) (tea.KeyMsg, error) {
+	var alt bool
+	if rest, ok := strings.CutPrefix(s, "alt+"); ok && rest != "" {
+		alt, s = true, rest
+	}
+
+	// Is it a named key?
+	if kt, ok := keyTypes[s]; ok {
+		if kt == tea.KeySpace {
+			return tea.KeyMsg{Type: kt, Runes: []rune{' '}, Alt: alt}, nil
+		}
+		return tea.KeyMsg{Type: kt, Alt: alt}, nil
+	}
+
+	// Otherwise it should be a single character
+	if rs := []rune(s); len(rs) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: rs, Alt: alt}, nil
+	}
+	return tea.KeyMsg{}, fmt.Errorf("unknown key %q", s)
+}
+
+// typeText converts a string into the key messages produced by
+// typing it one character at a time.
+func typeText(s string) []tea.Msg {
+	var msgs []tea.Msg
+	for _, r := range s {
+		if r == ' ' {
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+			continue
+		}
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+// driver feeds messages straight into a model's Update method without
+// starting a program or touching the terminal, so a key sequence can
+// be scripted against the game and the rendered output inspected.
+//
+// Commands returned by the model are not run.
+type driver struct {
+	m tea.Model
+}
+
+func newDriver(m tea.Model) *driver {
+	return &driver{m: m}
+}
+
+// size sends a window size message to the model.
+func (d *driver) size(w, h int) *driver {
+	return d.send(tea.WindowSizeMsg{Width: w, Height: h})
+}
+
+// send passes each message to the model in order.
+func (d *driver) send(msgs ...tea.Msg) *driver {
+	for _, msg := range msgs {
+		d.m, _ = d.m.Update(msg)
+	}
+	return d
+}
+
+// keys sends each of the named keys (see parseKey) to the model.
+func (d *driver) keys(names ...string) (*driver, error) {
+	for _, n := range names {
+		k, err := parseKey(n)
+		if err != nil {
+			return d, err
+		}
+		d.send(k)
+	}
+	return d, nil
+}
+
+// typ types the text into the model, one character at a time.
+func (d *driver) typ(s string) *driver {
+	return d.send(typeText(s)...)
+}
+
+// view renders the model's current view.
+func (d *driver) view() string {
+	return d.m.View()
+}
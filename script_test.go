@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseKey(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want tea.KeyMsg
+	}{
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}},
+		{"ctrl+c", tea.KeyMsg{Type: tea.KeyCtrlC}},
+		{"alt+t", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true}},
+		{"space", tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}},
+		{"x", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}},
+	} {
+		got, err := parseKey(tt.name)
+		if err != nil {
+			t.Errorf("parseKey(%q): %v", tt.name, err)
+			continue
+		}
+		if got.String() != tt.want.String() || got.Alt != tt.want.Alt {
+			t.Errorf("parseKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if _, err := parseKey("nonsense"); err == nil {
+		t.Error("parseKey(\"nonsense\") didn't fail")
+	}
+}
+
+func TestTypeText(t *testing.T) {
+	var got string
+	for _, msg := range typeText("big city") {
+		got += msg.(tea.KeyMsg).String()
+	}
+	if got != "big city" {
+		t.Errorf("typeText(\"big city\") typed %q", got)
+	}
+}
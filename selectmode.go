@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// superscripts are the digits used to number clues in select mode.
+var superscripts = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// superscript formats n in superscript digits.
+func superscript(n int) string {
+	var b strings.Builder
+	for _, d := range strconv.Itoa(n) {
+		b.WriteRune(superscripts[d-'0'])
+	}
+	return b.String()
+}
+
+// numberClues labels each active clue with its number, for select
+// mode.
+func numberClues(segs []segment) []segment {
+	var out []segment
+	n := 0
+	for _, seg := range segs {
+		if seg.clue {
+			n++
+			out = append(out, segment{text: superscript(n)})
+		}
+		out = append(out, seg)
+	}
+	return out
+}
+
+// activeClues returns the active clues, without their brackets, in the
+// order they appear.
+func (m model) activeClues() []string {
+	var clues []string
+	for _, seg := range m.segs {
+		if seg.clue {
+			clues = append(clues, seg.text[1:len(seg.text)-1])
+		}
+	}
+	return clues
+}
+
+// setSelectMode turns select mode on or off. In select mode, the clues
+// are numbered, and typing a clue's number opens a prompt for just that
+// clue, rather than answers being checked against every clue.
+func (m *model) setSelectMode(on bool) {
+	m.selectMode = on
+	m.target = ""
+	m.txtin.Reset()
+	m.renderBody()
+}
+
+// checkAnswerMode returns an error for an unknown answer mode.
+func checkAnswerMode(mode string) error {
+	switch mode {
+	case "", "type", "select":
+		return nil
+	}
+	return fmt.Errorf("unknown answer mode %q (expected type or select)", mode)
+}
+
+// updateSelect handles the keys for choosing a clue by its number,
+// reporting whether msg was one of them. With nine clues or fewer, a
+// single digit chooses one.
+func (m model) updateSelect(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	clues := m.activeClues()
+	switch {
+	case key.Matches(msg, keys.Submit):
+		if m.txtin.Value() == "" {
+			return m, nil, true
+		}
+	case msg.Type == tea.KeyBackspace:
+		var cmd tea.Cmd
+		m.txtin, cmd = m.txtin.Update(msg)
+		return m, cmd, true
+	case msg.Type == tea.KeyRunes && !msg.Alt && len(msg.Runes) == 1 && msg.Runes[0] >= '0' && msg.Runes[0] <= '9':
+		m.txtin.SetValue(m.txtin.Value() + string(msg.Runes))
+		m.txtin.CursorEnd()
+		if len(clues) > 9 {
+			return m, nil, true
+		}
+	case msg.Type == tea.KeyRunes:
+		// Only digits choose a clue
+		return m, nil, true
+	default:
+		return m, nil, false
+	}
+
+	n, _ := strconv.Atoi(m.txtin.Value())
+	m.txtin.Reset()
+	if n < 1 || n > len(clues) {
+		m.toast = tr("There's no clue %d", n)
+		return m, nil, true
+	}
+	m.target = clues[n-1]
+	return m, nil, true
+}
+
+// selectPrompt renders the input in select mode, under either the
+// chosen clue or a reminder to choose one.
+func (m model) selectPrompt() string {
+	if m.target == "" {
+		return unplayedStyle.Render(tr("Type a clue's number")) + "\n" + m.txtin.View()
+	}
+	return activeStyle.Render("["+m.target+"]") + "\n" + m.txtin.View()
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+)
+
+// apiHandler serves an engine's puzzles and games as JSON over HTTP,
+// for another front end (e.g. a web or mobile app) to play with:
+//
+//	GET  /puzzles/{date}          the puzzle, without its answers
+//	GET  /games/{date}            the game's state
+//	POST /games/{date}/guesses    make a guess: {"answer": "..."}
+//
+// Pages on other origins can only call it if they're allowed (see
+// withCORS), as any page open in the browser could otherwise read the
+// games and spend guesses on them.
+func apiHandler(e *engine, origins []string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /puzzles/{date}", func(w http.ResponseWriter, r *http.Request) {
+		p, err := e.puzzle(r.PathValue("date"))
+		writeAPIResult(w, p, err)
+	})
+	mux.HandleFunc("GET /games/{date}", func(w http.ResponseWriter, r *http.Request) {
+		g, err := e.game(r.PathValue("date"))
+		writeAPIResult(w, g, err)
+	})
+	mux.HandleFunc("POST /games/{date}/guesses", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Answer string `json:"answer"`
+		}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
+			return
+		}
+		res, err := e.guess(r.PathValue("date"), body.Answer)
+		writeAPIResult(w, res, err)
+	})
+	return withCORS(mux, origins)
+}
+
+// withCORS lets pages on the given origins (or any, for "*") call h,
+// answering their preflight requests. Requests from pages on other
+// origins are refused outright, as a form or a no-cors fetch can post
+// a guess without asking first. Programs other than browsers don't
+// send an Origin, and are let through.
+func withCORS(h http.Handler, origins []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		if !slices.Contains(origins, origin) && !slices.Contains(origins, "*") {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "pages on " + origin + " aren't allowed (see brack serve --allow-origin)"})
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// writeAPIResult responds with v, or the error as JSON: {"error":
+// "..."}.
+func writeAPIResult(w http.ResponseWriter, v any, err error) {
+	if err == nil {
+		writeJSON(w, http.StatusOK, v)
+		return
+	}
+
+	status := http.StatusInternalServerError
+	var ce coolingError
+	switch {
+	case errors.Is(err, errInvalidDate), errors.Is(err, errEmptyAnswer):
+		status = http.StatusBadRequest
+	case errors.Is(err, errPuzzleLocked), errors.Is(err, errPuzzleFinished):
+		status = http.StatusConflict
+	case errors.As(err, &ce):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(ce.left.Seconds()))))
+		status = http.StatusTooManyRequests
+	default:
+		slog.Error("api request failed", "err", err)
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
+
+// runServe serves the API on addr until interrupted, to pages on the
+// given origins as well as other programs.
+func runServe(ctx context.Context, w io.Writer, addr string, origins []string, s *store, cfg config) error {
+	return serveUntilInterrupted(ctx, w, addr, "the brack API", apiHandler(newEngine(s, cfg), origins))
+}
+
+// serveUntilInterrupted serves h on addr until ctx is done or the
+// process is interrupted, saying what's being served on w.
+func serveUntilInterrupted(ctx context.Context, w io.Writer, addr, what string, h http.Handler) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: h}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	fmt.Fprintf(w, "Serving %s on %s (ctrl+c to stop)\n", what, ln.Addr())
+	slog.Info("serving", "what", what, "addr", ln.Addr().String())
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPICORS(t *testing.T) {
+	s, err := openStore(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newEngine(s, defaultConfig())
+	const app = "http://localhost:3000"
+
+	// Programs other than browsers don't send an Origin
+	w := httptest.NewRecorder()
+	apiHandler(e, nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/games/not-a-date", nil))
+	if w.Code != http.StatusBadRequest || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("a request without an Origin got status %d, headers %v", w.Code, w.Header())
+	}
+
+	for _, tc := range []struct {
+		name    string
+		origins []string
+		method  string
+		origin  string
+		allowed bool
+	}{
+		{"off by default", nil, http.MethodGet, app, false},
+		{"no preflight by default", nil, http.MethodOptions, app, false},
+		{"allowed origin", []string{app}, http.MethodGet, app, true},
+		{"allowed preflight", []string{app}, http.MethodOptions, app, true},
+		{"other origin", []string{app}, http.MethodPost, "https://evil.example", false},
+		{"any origin", []string{"*"}, http.MethodGet, "https://example.com", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := "/games/not-a-date"
+			if tc.method == http.MethodPost {
+				path += "/guesses"
+			}
+			r := httptest.NewRequest(tc.method, path, nil)
+			r.Header.Set("Origin", tc.origin)
+			if tc.method == http.MethodOptions {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			apiHandler(e, tc.origins).ServeHTTP(w, r)
+
+			got := w.Header().Get("Access-Control-Allow-Origin")
+			if tc.allowed && got != tc.origin || !tc.allowed && got != "" {
+				t.Errorf("Access-Control-Allow-Origin is %q (status %d)", got, w.Code)
+			}
+			if !tc.allowed && w.Code != http.StatusForbidden {
+				t.Errorf("a request from a page on %s got status %d, want it refused", tc.origin, w.Code)
+			}
+			if tc.allowed && tc.method == http.MethodOptions && w.Code != http.StatusNoContent {
+				t.Errorf("preflight status is %d", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sharedFile is a game kept in a file of its own, in a folder synced
+// between machines (e.g. with Dropbox or Syncthing), so a household
+// can chip away at one puzzle whenever each of them has a moment.
+// Every save bumps the revision, and is only written over the revision
+// it was based on, so no one's guesses overwrite someone else's.
+type sharedFile struct {
+	Revision  int        `json:"revision"`
+	Puzzle    puzzledata `json:"puzzle"`
+	Game      gamestate  `json:"game"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// sharedGame is the shared file a game is played in: the revision the
+// game is based on, and the file's modification time then.
+type sharedGame struct {
+	path string
+	rev  int
+	by   string // who saved that revision
+	mod  time.Time
+}
+
+func readSharedFile(path string) (sharedFile, time.Time, error) {
+	var f sharedFile
+	fi, err := os.Stat(path)
+	if err != nil {
+		return f, time.Time{}, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return f, time.Time{}, err
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return f, time.Time{}, fmt.Errorf("%s isn't a shared game: %w", path, err)
+	}
+	return f, fi.ModTime(), nil
+}
+
+// writeSharedFile writes a shared game by renaming a temporary file
+// over it, so a sync client never picks up half of it.
+func writeSharedFile(path string, f sharedFile) (time.Time, error) {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return time.Time{}, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".brack-*.tmp")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return time.Time{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return time.Time{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return time.Time{}, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fi.ModTime(), nil
+}
+
+// openShared opens a shared game, starting it with the puzzle for d if
+// the file doesn't exist yet. Once it does, its own puzzle is played.
+func openShared(path string, s *store, d time.Time) (sharedFile, time.Time, error) {
+	f, mod, err := readSharedFile(path)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return f, mod, err
+	}
+	p, err := loadPuzzle(s, d)
+	if err != nil {
+		return f, mod, err
+	}
+	f = sharedFile{Puzzle: p, UpdatedAt: time.Now()}
+	mod, err = writeSharedFile(path, f)
+	return f, mod, err
+}
+
+// saveShared saves the game to its shared file, unless someone else
+// saved it first, in which case watchShared loads theirs instead.
+func (m *model) saveShared() {
+	if noSave {
+		return
+	}
+	f, _, err := readSharedFile(m.shared.path)
+	if err != nil {
+		m.saveErr = err
+		slog.Error("failed to read the shared game", "path", m.shared.path, "err", err)
+		return
+	}
+	gs := m.gamestate()
+	if f.Revision != m.shared.rev {
+		if !sameProgress(gs, f.Game) {
+			m.toast = tr("%s played meanwhile, so your last guess wasn't saved", f.UpdatedBy)
+		}
+		return
+	}
+	if sameProgress(gs, f.Game) {
+		return
+	}
+
+	f.Revision++
+	f.Game = gs
+	f.UpdatedBy, f.UpdatedAt = m.player, time.Now()
+	mod, err := writeSharedFile(m.shared.path, f)
+	m.saveErr = err
+	if err != nil {
+		slog.Error("failed to save the shared game", "path", m.shared.path, "err", err)
+		return
+	}
+	m.shared.rev, m.shared.by, m.shared.mod = f.Revision, f.UpdatedBy, mod
+}
+
+// watchShared loads the shared game if someone else has saved it since
+// (checking at most every watchInterval, unless now is set).
+func (m model) watchShared(now bool) (model, tea.Cmd) {
+	if !now && time.Since(m.watchedAt) < watchInterval {
+		return m, nil
+	}
+	m.watchedAt = time.Now()
+	if fi, err := os.Stat(m.shared.path); err != nil || fi.ModTime().Equal(m.shared.mod) {
+		return m, nil
+	}
+	f, mod, err := readSharedFile(m.shared.path)
+	if err != nil {
+		slog.Warn("failed to read the shared game", "path", m.shared.path, "err", err)
+		return m, nil
+	}
+	if f.Revision == m.shared.rev {
+		m.shared.mod = mod
+		return m, nil
+	}
+
+	slog.Info("loading shared game", "path", m.shared.path, "revision", f.Revision, "by", f.UpdatedBy)
+	m.store.data.Games[m.data.PuzzleDate] = f.Game
+	shared := &sharedGame{path: m.shared.path, rev: f.Revision, by: f.UpdatedBy, mod: mod}
+	n, cmd := m.switchPuzzle(m.data, false)
+	n.shared, n.watchedAt = shared, m.watchedAt
+	if m.toast == "" {
+		n.toast = tr("🏠 %s played: %d/%d solved", f.UpdatedBy, n.correct, len(n.data.Solutions))
+	} else {
+		n.toast = m.toast
+	}
+	return n, cmd
+}
+
+// submitShared makes a guess in a shared game, on top of anything
+// someone else has saved in the meantime.
+func (m model) submitShared(in string) (model, tea.Cmd) {
+	m, cmd := m.watchShared(true)
+	if m.done {
+		return m, cmd
+	}
+	n, guess := m.submit(in)
+	n.save()
+	n, load := n.watchShared(true)
+	return n, tea.Batch(cmd, guess, load)
+}
+
+// runShared plays the game in a shared file, starting it with the
+// puzzle for the date argument if it doesn't exist yet.
+func runShared(path, arg, name string) error {
+	d, err := parseDateArg(arg)
+	if err != nil {
+		return err
+	}
+	if err := requireTerminal(); err != nil {
+		return err
+	}
+
+	// The puzzle is fetched through your own store, which caches it
+	s, cfg, err := openUserGames()
+	if err != nil {
+		return err
+	}
+	setLocale(cfg.Locale)
+	f, mod, err := openShared(path, s, d)
+	if err != nil {
+		return err
+	}
+	if arg != "" && f.Puzzle.PuzzleDate != d.Format(time.DateOnly) {
+		slog.Warn("the shared game is for another puzzle", "path", path, "date", f.Puzzle.PuzzleDate)
+	}
+
+	// The game itself is kept in memory, and saved to the file
+	mem, err := openStore(memoryPath)
+	if err != nil {
+		return err
+	}
+	if f.Game.Date != "" {
+		mem.data.Games[f.Game.Date] = f.Game
+	}
+	setTheme(cfg.Theme)
+	m := newModel(f.Puzzle, mem, cfg)
+	m.shared = &sharedGame{path: path, rev: f.Revision, by: f.UpdatedBy, mod: mod}
+	m.player = name
+	_, err = runViews(m)
+	return err
+}
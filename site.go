@@ -0,0 +1,235 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed site/index.html
+var siteTemplate string
+
+// The layout of the site's charts, in SVG units.
+const (
+	siteCell      = 12 // a day in the heatmap
+	siteGap       = 3
+	siteChartW    = 640
+	siteChartH    = 160
+	siteTimeBars  = 30 // the latest timed solves charted
+	siteMonthBars = 12
+)
+
+// siteData is what the site's page is made from. Positions are worked
+// out here, so the template only has to draw them.
+type siteData struct {
+	Generated     string
+	Played        int
+	Solved        int
+	Streak        int
+	LongestStreak int
+	AvgTime       string
+	AvgIncorrect  string
+
+	HeatmapW, HeatmapH int
+	ChartW, ChartH     int
+	Days               []siteRect
+	MonthLabels        []siteLabel
+	TimeBars           []siteRect
+	MaxTime            string
+	MonthBars          []siteRect
+	BarLabels          []siteLabel
+}
+
+type siteRect struct {
+	X, Y, W, H int
+	Class      string
+	Title      string
+}
+
+type siteLabel struct {
+	X, Y int
+	Text string
+}
+
+// longestStreak is the most days in a row the puzzle was solved.
+func longestStreak(s *store, today time.Time) int {
+	var dates []string
+	for date, gs := range s.data.Games {
+		if gs.Done && !gs.GaveUp {
+			dates = append(dates, date)
+		}
+	}
+	slices.Sort(dates)
+
+	var best, n int
+	var prev time.Time
+	for _, date := range dates {
+		day, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+		if err != nil || day.After(today) {
+			continue
+		}
+		if !prev.IsZero() && day.Equal(prev.AddDate(0, 0, 1)) {
+			n++
+		} else {
+			n = 1
+		}
+		best, prev = max(best, n), day
+	}
+	return best
+}
+
+// heatmapClass is the class of a day in the heatmap, by how its game
+// went, and its tooltip.
+func heatmapClass(s *store, day time.Time) (string, string) {
+	date := day.Format(time.DateOnly)
+	gs, ok := s.game(date)
+	switch {
+	case !ok:
+		return "unplayed", date + ": not played"
+	case gs.GaveUp:
+		return "gaveup", date + ": gave up"
+	case !gs.Done:
+		return "progress", date + ": in progress"
+	}
+	title := fmt.Sprintf("%s: solved, %d incorrect", date, gs.Incorrect)
+	if gs.ElapsedSeconds > 0 {
+		title += ", " + formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second)
+	}
+	return "solved", title
+}
+
+// buildSiteData works out the site's stats and charts. Like the share
+// text, nothing in it gives away an answer.
+func buildSiteData(s *store, now time.Time) siteData {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	d := siteData{
+		Generated:     now.Format("January 2, 2006"),
+		Streak:        currentStreak(s, today),
+		LongestStreak: longestStreak(s, today),
+		AvgTime:       "-",
+		AvgIncorrect:  "-",
+		ChartW:        siteChartW,
+		ChartH:        siteChartH,
+	}
+	var st stats
+	for _, gs := range s.allGames() {
+		if !gs.Expert {
+			st.add(gs)
+		}
+	}
+	d.Played, d.Solved = st.played, st.solved
+	if st.timed > 0 {
+		d.AvgTime = formatElapsed(st.solveTime / time.Duration(st.timed))
+	}
+	if st.solved > 0 {
+		d.AvgIncorrect = fmt.Sprintf("%.1f", float64(st.incorrect)/float64(st.solved))
+	}
+
+	// The heatmap: the last year, a column per week, starting on Monday
+	first := weekStart(today).AddDate(0, 0, -7*52)
+	step := siteCell + siteGap
+	top := 16 // room for the month labels
+	for wk := range 53 {
+		for dow := range 7 {
+			day := first.AddDate(0, 0, 7*wk+dow)
+			if day.After(today) {
+				continue
+			}
+			class, title := heatmapClass(s, day)
+			d.Days = append(d.Days, siteRect{X: wk * step, Y: top + dow*step, W: siteCell, H: siteCell, Class: class, Title: title})
+			if day.Day() == 1 {
+				d.MonthLabels = append(d.MonthLabels, siteLabel{X: wk * step, Y: 11, Text: day.Format("Jan")})
+			}
+		}
+	}
+	d.HeatmapW, d.HeatmapH = 53*step, top+7*step
+
+	// The solve times of the latest timed solves
+	var timed []gamestate
+	for date, gs := range s.data.Games {
+		if _, err := time.Parse(time.DateOnly, date); err == nil && gs.Done && !gs.GaveUp && gs.ElapsedSeconds > 0 {
+			timed = append(timed, gs)
+		}
+	}
+	slices.SortFunc(timed, func(a, b gamestate) int { return strings.Compare(a.Date, b.Date) })
+	timed = timed[max(len(timed)-siteTimeBars, 0):]
+	var longest int64
+	for _, gs := range timed {
+		longest = max(longest, gs.ElapsedSeconds)
+	}
+	if longest > 0 {
+		d.MaxTime = formatElapsed(time.Duration(longest) * time.Second)
+		w := siteChartW / siteTimeBars
+		for i, gs := range timed {
+			h := max(int(int64(siteChartH)*gs.ElapsedSeconds/longest), 1)
+			d.TimeBars = append(d.TimeBars, siteRect{
+				X: i * w, Y: siteChartH - h, W: w - 2, H: h, Class: "bar",
+				Title: fmt.Sprintf("%s: %s", gs.Date, formatElapsed(time.Duration(gs.ElapsedSeconds)*time.Second)),
+			})
+		}
+	}
+
+	// Solves per month, over the last year
+	counts := make([]int, siteMonthBars)
+	var most int
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local)
+	for i := range counts {
+		_, counts[i] = s.monthSummary(month.AddDate(0, i-siteMonthBars+1, 0).Format("2006-01"))
+		most = max(most, counts[i])
+	}
+	w := siteChartW / siteMonthBars
+	for i, n := range counts {
+		m := month.AddDate(0, i-siteMonthBars+1, 0)
+		h := 0
+		if most > 0 {
+			h = siteChartH * n / most
+		}
+		d.MonthBars = append(d.MonthBars, siteRect{
+			X: i * w, Y: siteChartH - h, W: w - 4, H: h, Class: "bar",
+			Title: fmt.Sprintf("%s: %d solved", m.Format("January 2006"), n),
+		})
+		d.BarLabels = append(d.BarLabels, siteLabel{X: i*w + (w-4)/2, Y: siteChartH + 14, Text: m.Format("Jan")})
+	}
+	return d
+}
+
+// writeSite writes the stats page.
+func writeSite(w io.Writer, s *store, now time.Time) error {
+	t, err := template.New("site").Parse(siteTemplate)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, buildSiteData(s, now))
+}
+
+// runSiteBuild builds the static stats site into dir, e.g. to publish
+// with GitHub Pages.
+func runSiteBuild(w io.Writer, dir string) error {
+	s, err := openUserStore()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "index.html")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeSite(f, s, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Wrote %s\n", path)
+	return nil
+}
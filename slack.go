@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackMaxSkew is how old a request's timestamp can be, so a captured
+// request can't be replayed later.
+const slackMaxSkew = 5 * time.Minute
+
+// slackUsage is the reply to /brack help, or a command it doesn't know.
+const slackUsage = "Play today's Bracket City puzzle with the channel:\n" +
+	"`/brack show` shows the channel's game\n" +
+	"`/brack guess ANSWER` guesses an answer"
+
+// slackBot answers a Slack slash command (/brack), each channel
+// playing its own game of today's puzzle.
+type slackBot struct {
+	secret string
+	games  *channelGames
+}
+
+// verify checks a request's signature, made with the app's signing
+// secret (see https://api.slack.com/authentication/verifying-requests-from-slack).
+func (b *slackBot) verify(r *http.Request, body []byte) bool {
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || time.Since(time.Unix(secs, 0)).Abs() > slackMaxSkew {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(b.secret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(want), []byte(r.Header.Get("X-Slack-Signature")))
+}
+
+func (b *slackBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "can't read the request", http.StatusBadRequest)
+		return
+	}
+	if !b.verify(r, body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	text, ephemeral := b.run(form.Get("channel_id"), form.Get("user_id"), form.Get("text"))
+	kind := "in_channel"
+	if ephemeral {
+		kind = "ephemeral"
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"response_type": kind, "text": text})
+}
+
+// run runs a /brack command, returning the reply, and whether only
+// the user who sent it should see it (e.g. for errors).
+func (b *slackBot) run(channel, user, text string) (string, bool) {
+	sub, answer, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if sub != "show" && sub != "guess" || sub == "guess" && strings.TrimSpace(answer) == "" {
+		return slackUsage, true
+	}
+	e, err := b.games.engine(channel)
+	if err != nil {
+		slog.Error("failed to open the channel's games", "channel", channel, "err", err)
+		return "Couldn't open this channel's game: " + err.Error(), true
+	}
+
+	if sub == "show" {
+		g, err := e.game(today())
+		if err != nil {
+			return err.Error(), true
+		}
+		return chatGame(g), false
+	}
+	res, err := e.guess(today(), answer)
+	if err != nil {
+		return chatError(err), true
+	}
+	return chatGuess("<@"+user+">", res), false
+}
+
+// runSlackBot serves the /brack slash command on addr until
+// interrupted.
+func runSlackBot(ctx context.Context, w io.Writer, addr string, cfg config) error {
+	if cfg.SlackSigningSecret == "" {
+		return errors.New("no Slack signing secret, set slack_signing_secret in the config file")
+	}
+	bot := &slackBot{secret: cfg.SlackSigningSecret, games: newChannelGames("slack", cfg)}
+	return serveUntilInterrupted(ctx, w, addr, "the Slack /brack command", bot)
+}
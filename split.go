@@ -0,0 +1,94 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// On terminals at least splitMinWidth wide, the calendar is shown in a
+// pane beside the game, calendarPaneWidth wide (including its border).
+const (
+	splitMinWidth     = 140
+	calendarPaneWidth = 50
+)
+
+// Styles for the calendar pane's border, highlighted when it has focus.
+var (
+	paneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(colorGrey).
+			Padding(0, 1)
+	focusedPaneStyle = paneStyle.
+				BorderForeground(colorYellow)
+)
+
+// newCalendarPane creates the calendar shown beside the game, with the
+// puzzle's day selected.
+func newCalendarPane(s *store, date string) calendarModel {
+	c := newCalendarModel(s, time.Now())
+	c.pane = true
+	if d, err := time.ParseInLocation(time.DateOnly, date, time.Local); err == nil && !d.After(c.today) {
+		c.sel = d
+	}
+	return c
+}
+
+// resize sizes the game to the terminal, making room for the calendar
+// pane when it's wide enough.
+func (m *model) resize(w, h int) {
+	m.split = m.store != nil && w >= splitMinWidth
+	if !m.split {
+		m.w, m.h = w, h
+		m.focusCalendar(false)
+		return
+	}
+	m.w, m.h = w-calendarPaneWidth-1, h
+	m.cal.w, m.cal.h = calendarPaneWidth-4, h-2
+}
+
+// focusCalendar moves the focus to the calendar pane, or back to the
+// game.
+func (m *model) focusCalendar(focus bool) {
+	m.calFocus = focus
+	if focus {
+		m.txtin.Blur()
+	} else {
+		m.txtin.Focus()
+	}
+}
+
+// updateCalendarPane handles keys while the calendar pane has focus.
+// Choosing a day loads its puzzle in place of the current one.
+func (m model) updateCalendarPane(msg tea.KeyMsg) (model, tea.Cmd) {
+	if key.Matches(msg, keys.Close) && !m.cal.month {
+		m.focusCalendar(false)
+		return m, nil
+	}
+
+	// The pane never quits the program, so drop its command
+	c, _ := m.cal.Update(msg)
+	m.cal = c.(calendarModel)
+	if !m.cal.chosen {
+		return m, nil
+	}
+	m.cal.chosen = false
+	m.focusCalendar(false)
+	m.toast = tr("Loading the puzzle for %s...", m.cal.sel.Format(time.DateOnly))
+	return m, loadPuzzleCmd(m.store, m.cal.sel)
+}
+
+// splitView puts the calendar pane beside the game.
+func (m model) splitView(game string) string {
+	style := paneStyle
+	if m.calFocus {
+		style = focusedPaneStyle
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(m.w).Render(game),
+		" ",
+		style.Width(calendarPaneWidth-2).Render(m.cal.View()),
+	)
+}
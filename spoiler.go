@@ -0,0 +1,37 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// solved reports whether the puzzle for a date has been solved, in the
+// current game or an earlier attempt (giving up doesn't count).
+func (s *store) solved(date string) bool {
+	games := s.attempts(date)
+	if gs, ok := s.game(date); ok {
+		games = append(games[:len(games):len(games)], gs)
+	}
+	_, ok := bestOf(games)
+	return ok
+}
+
+// spoilerLocked reports whether showing the puzzle's answers needs
+// confirming first: with spoiler_lock set, after giving up on a puzzle
+// that was never solved, so answers aren't revealed by a stray key.
+func (m model) spoilerLocked() bool {
+	if !m.cfg.SpoilerLock || !m.gaveUp || m.spoilersOK {
+		return false
+	}
+	return m.store == nil || !m.store.solved(m.data.PuzzleDate)
+}
+
+// showSpoilers does something that reveals the puzzle's answers,
+// asking first if they're locked.
+func (m model) showSpoilers(action func(m model) (model, tea.Cmd)) (model, tea.Cmd) {
+	if !m.spoilerLocked() {
+		return action(m)
+	}
+	m.confirmAction(tr("You haven't solved this puzzle. Show its answers anyway?"), func(m model) (model, tea.Cmd) {
+		m.spoilersOK = true
+		return action(m)
+	})
+	return m, nil
+}
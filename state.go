@@ -1 +1,445 @@
 package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// gamestate is the saved progress on a single day's puzzle.
+type gamestate struct {
+	Date      string `json:"date"`
+	State     string `json:"state"`
+	Correct   int    `json:"correct"`
+	Incorrect int    `json:"incorrect"`
+	Chars     int    `json:"chars"`
+	Done      bool   `json:"done"`
+
+	// GaveUp is set when the game was ended without solving it.
+	GaveUp bool `json:"gave_up,omitempty"`
+
+	// Nudges is how many times the game offered a nudge because the
+	// player seemed stuck.
+	Nudges int `json:"nudges,omitempty"`
+
+	// Assisted is set once the letter helper has been used.
+	Assisted bool `json:"assisted,omitempty"`
+
+	// HintsUsed counts the hints taken: opening the letter helper,
+	// and being shown partial credit for a guess.
+	HintsUsed int `json:"hints_used,omitempty"`
+
+	// Expert is set for a game played in expert mode, solving the
+	// deepest clues first. Its stats are kept separately.
+	Expert bool `json:"expert,omitempty"`
+
+	// Penalties are what the game's score is worked out with (see
+	// score). Games from before scoring was added have none.
+	Penalties *penalties `json:"penalties,omitempty"`
+
+	// ElapsedSeconds is the time spent solving, not counting time
+	// spent paused.
+	ElapsedSeconds int64 `json:"elapsed_seconds"`
+
+	// SolveTimes is the elapsed seconds at each correct answer, for
+	// racing against as a ghost.
+	SolveTimes []int64 `json:"solve_times,omitempty"`
+
+	// Events logs each guess and hint, for exporting.
+	Events []gameEvent `json:"events,omitempty"`
+}
+
+// storedata is the on-disk format of the store.
+type storedata struct {
+	Version  int                   `json:"version"`
+	Metadata map[string]string     `json:"metadata"`
+	Puzzles  map[string]puzzledata `json:"puzzles"`
+	Games    map[string]gamestate  `json:"games"`
+
+	// RawPuzzles holds the API's response for each cached puzzle, so
+	// a newer version of brack can decode it again.
+	RawPuzzles map[string]json.RawMessage `json:"raw_puzzles,omitempty"`
+
+	// Attempts holds the earlier, archived, attempts at each
+	// date's puzzle, oldest first.
+	Attempts map[string][]gamestate `json:"attempts"`
+
+	// Cards holds the flashcards reviewed so far, by clue.
+	Cards map[string]card `json:"cards,omitempty"`
+
+	// Notes holds the player's notes on each date's puzzle.
+	Notes map[string]string `json:"notes,omitempty"`
+}
+
+// storeVersion is the current version of the store's data (see
+// migrations).
+const storeVersion = 2
+
+// store persists game state in a JSON file.
+type store struct {
+	path string
+	data storedata
+
+	// modTime is when the file was last read or written by this
+	// process, to tell if another one has written to it since (see
+	// mergeFromDisk).
+	modTime time.Time
+}
+
+// dataDir returns the directory brack keeps its data in, following
+// the XDG base directory spec where it applies.
+func dataDir() (string, error) {
+	if d := os.Getenv("XDG_DATA_HOME"); d != "" {
+		return filepath.Join(d, "brack"), nil
+	}
+	if runtime.GOOS == "windows" {
+		if d := os.Getenv("LOCALAPPDATA"); d != "" {
+			return filepath.Join(d, "brack"), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "brack"), nil
+}
+
+func defaultStorePath() (string, error) {
+	d, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, "brack.json"), nil
+}
+
+// memoryPath is the db_path for a database kept in memory, which
+// starts empty and is never written to disk, for tests and throwaway
+// environments such as containers.
+const memoryPath = ":memory:"
+
+// storePath returns the path of the database, from the config if it's
+// set there.
+func storePath(cfg config) (string, error) {
+	if cfg.DBPath == memoryPath {
+		return memoryPath, nil
+	}
+	path := expandHome(cfg.DBPath)
+	if path == "" {
+		var err error
+		if path, err = defaultStorePath(); err != nil {
+			return "", err
+		}
+	}
+
+	// Profiles are kept in a directory beside the main database
+	if cfg.Profile != "" {
+		return filepath.Join(filepath.Dir(path), "profiles", cfg.Profile+".json"), nil
+	}
+	return path, nil
+}
+
+// openUserStore opens the store at the location set by the user's
+// config (or the default).
+func openUserStore() (*store, error) {
+	s, _, err := openUserGames()
+	return s, err
+}
+
+// openUserGames opens the user's store, returning it with their config,
+// for the commands that play games without the TUI.
+func openUserGames() (*store, config, error) {
+	cfg, err := loadUserConfig()
+	if err != nil {
+		return nil, cfg, err
+	}
+	path, err := storePath(cfg)
+	if err != nil {
+		return nil, cfg, err
+	}
+	s, err := openStore(path)
+	return s, cfg, err
+}
+
+// openStore loads the store at path. A missing file is treated as an
+// empty store.
+func openStore(path string) (*store, error) {
+	s := &store{
+		path: path,
+		data: storedata{
+			Version:    storeVersion,
+			Metadata:   make(map[string]string),
+			Puzzles:    make(map[string]puzzledata),
+			RawPuzzles: make(map[string]json.RawMessage),
+			Cards:      make(map[string]card),
+			Notes:      make(map[string]string),
+			Games:      make(map[string]gamestate),
+			Attempts:   make(map[string][]gamestate),
+		},
+	}
+
+	if path == memoryPath {
+		return s, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, prepareEncryption()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b, err = decryptStore(b); err != nil {
+		return nil, err
+	}
+	// Databases from before versions were kept don't have one, and need
+	// every migration
+	s.data.Version = 0
+	if err := json.Unmarshal(b, &s.data); err != nil {
+		return nil, err
+	}
+	if err := prepareEncryption(); err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(path); err == nil {
+		s.modTime = fi.ModTime()
+	}
+	if s.data.Metadata == nil {
+		s.data.Metadata = make(map[string]string)
+	}
+	if s.data.Puzzles == nil {
+		s.data.Puzzles = make(map[string]puzzledata)
+	}
+	if s.data.Games == nil {
+		s.data.Games = make(map[string]gamestate)
+	}
+	if s.data.Cards == nil {
+		s.data.Cards = make(map[string]card)
+	}
+	if s.data.Notes == nil {
+		s.data.Notes = make(map[string]string)
+	}
+	if s.data.RawPuzzles == nil {
+		s.data.RawPuzzles = make(map[string]json.RawMessage)
+	}
+	if s.data.Attempts == nil {
+		s.data.Attempts = make(map[string][]gamestate)
+	}
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+	s.redecodePuzzles()
+	slog.Debug("opened store", "path", path, "games", len(s.data.Games))
+	return s, nil
+}
+
+// puzzle returns the cached puzzle for the given date, if any.
+func (s *store) puzzle(date string) (puzzledata, bool) {
+	p, ok := s.data.Puzzles[date]
+	return p, ok
+}
+
+// savePuzzle caches a puzzle, along with the API's raw response if
+// there is one, and writes the store to disk.
+func (s *store) savePuzzle(date string, p puzzledata, raw json.RawMessage) error {
+	s.data.Puzzles[date] = p
+	if raw != nil {
+		s.data.RawPuzzles[date] = raw
+	}
+	return s.write()
+}
+
+// redecodePuzzles decodes the cached puzzles again from the API's raw
+// responses, so they're read the way this version of brack reads new
+// ones. Any it can't decode are left as they were.
+func (s *store) redecodePuzzles() {
+	for date, raw := range s.data.RawPuzzles {
+		p, err := decodePuzzle(raw)
+		if err != nil {
+			slog.Warn("couldn't decode cached puzzle", "date", date, "err", err)
+			continue
+		}
+		s.data.Puzzles[date] = p
+	}
+}
+
+// cachedPuzzles returns all the puzzles in the store.
+func (s *store) cachedPuzzles() []puzzledata {
+	ps := make([]puzzledata, 0, len(s.data.Puzzles))
+	for _, p := range s.data.Puzzles {
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// allGames returns every game in the store, current and archived.
+func (s *store) allGames() []gamestate {
+	var games []gamestate
+	for _, gs := range s.data.Games {
+		games = append(games, gs)
+	}
+	for _, as := range s.data.Attempts {
+		games = append(games, as...)
+	}
+	return games
+}
+
+// monthSummary counts the games played and solved in a month, given
+// as a "2006-01" prefix.
+func (s *store) monthSummary(month string) (played, solved int) {
+	for date, gs := range s.data.Games {
+		if !strings.HasPrefix(date, month+"-") {
+			continue
+		}
+		played++
+		if gs.Done && !gs.GaveUp {
+			solved++
+		}
+	}
+	return played, solved
+}
+
+// game returns the saved state for the given puzzle date, if any.
+func (s *store) game(date string) (gamestate, bool) {
+	gs, ok := s.data.Games[date]
+	return gs, ok
+}
+
+// saveGame records the game state and writes the store to disk.
+func (s *store) saveGame(gs gamestate) error {
+	s.data.Games[gs.Date] = gs
+	return s.write()
+}
+
+// archiveGame moves the saved state for a date to its list of past
+// attempts, so the puzzle can be played again, and writes the store
+// to disk.
+func (s *store) archiveGame(date string) error {
+	gs, ok := s.data.Games[date]
+	if !ok {
+		return nil
+	}
+	s.data.Attempts[date] = append(s.data.Attempts[date], gs)
+	delete(s.data.Games, date)
+	return s.write()
+}
+
+// attempts returns the archived attempts at a date's puzzle, oldest
+// first.
+func (s *store) attempts(date string) []gamestate {
+	return s.data.Attempts[date]
+}
+
+// cards returns the flashcards reviewed so far.
+func (s *store) cards() []card {
+	cs := make([]card, 0, len(s.data.Cards))
+	for _, c := range s.data.Cards {
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+// card returns the flashcard for a clue, if it's been reviewed.
+func (s *store) card(clue string) (card, bool) {
+	c, ok := s.data.Cards[clue]
+	return c, ok
+}
+
+// saveCard saves a flashcard and writes the store to disk.
+func (s *store) saveCard(c card) error {
+	s.data.Cards[c.Clue] = c
+	return s.write()
+}
+
+// datesBefore returns the dates of the cached puzzles, saved games and
+// archived attempts from before the cutoff date.
+func (s *store) datesBefore(cutoff string) (puzzles, games, attempts []string) {
+	for d := range s.data.Puzzles {
+		if d < cutoff {
+			puzzles = append(puzzles, d)
+		}
+	}
+	for d := range s.data.Games {
+		if d < cutoff {
+			games = append(games, d)
+		}
+	}
+	for d := range s.data.Attempts {
+		if d < cutoff {
+			attempts = append(attempts, d)
+		}
+	}
+	return puzzles, games, attempts
+}
+
+// deleteBefore removes the cached puzzles (and, unless puzzlesOnly is
+// set, the saved games and archived attempts) from before the cutoff
+// date, and writes the store to disk.
+func (s *store) deleteBefore(cutoff string, puzzlesOnly bool) error {
+	puzzles, games, attempts := s.datesBefore(cutoff)
+	for _, d := range puzzles {
+		delete(s.data.Puzzles, d)
+		delete(s.data.RawPuzzles, d)
+	}
+	if !puzzlesOnly {
+		for _, d := range games {
+			delete(s.data.Games, d)
+		}
+		for _, d := range attempts {
+			delete(s.data.Attempts, d)
+		}
+	}
+	return s.write()
+}
+
+// meta returns the metadata value for key, or "" if it isn't set.
+func (s *store) meta(key string) string {
+	return s.data.Metadata[key]
+}
+
+// setMeta sets a metadata value and writes the store to disk.
+func (s *store) setMeta(key, value string) error {
+	s.data.Metadata[key] = value
+	return s.write()
+}
+
+// noSave turns off writing stores to disk (see --no-save), so games
+// played, stats and streaks are all left as they were.
+var noSave bool
+
+// write saves the store to disk, via a temporary file so an
+// interrupted write can't corrupt it.
+func (s *store) write() error {
+	if noSave || s.path == memoryPath {
+		slog.Debug("not writing store", "path", s.path)
+		return nil
+	}
+	s.mergeFromDisk()
+	b, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if b, err = encryptStore(b); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(s.path); err == nil {
+		s.modTime = fi.ModTime()
+	}
+	slog.Debug("wrote store", "path", s.path)
+	return nil
+}
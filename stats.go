@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var _ tea.Model = statsModel{}
+
+// stats are the player's overall stats.
+type stats struct {
+	played, solved int
+	streak         int
+	incorrect      int
+	hints          int
+	solveTime      time.Duration
+	timed          int
+
+	// expert is the same for games in expert mode, which aren't
+	// counted in the rest.
+	expert *stats
+}
+
+// add counts a game in the stats.
+func (st *stats) add(gs gamestate) {
+	st.played++
+	if !gs.Done || gs.GaveUp {
+		return
+	}
+	st.solved++
+	st.incorrect += gs.Incorrect
+	st.hints += gs.HintsUsed
+	if gs.ElapsedSeconds > 0 {
+		st.solveTime += time.Duration(gs.ElapsedSeconds) * time.Second
+		st.timed++
+	}
+}
+
+// statsMsg is the result of computing the stats.
+type statsMsg stats
+
+// loadStats computes the stats in the background.
+func loadStats(s *store) tea.Cmd {
+	return func() tea.Msg {
+		st := stats{expert: &stats{}}
+		for _, gs := range s.allGames() {
+			if gs.Expert {
+				st.expert.add(gs)
+			} else {
+				st.add(gs)
+			}
+		}
+		st.streak = currentStreak(s, time.Now())
+		return statsMsg(st)
+	}
+}
+
+// statsModel shows the player's overall stats, over the game. They're
+// computed when it's first shown, with a spinner until they're ready.
+type statsModel struct {
+	store   *store
+	stats   *stats
+	spinner spinner.Model
+}
+
+func newStatsModel(s *store) statsModel {
+	return statsModel{store: s, spinner: spinner.New(spinner.WithSpinner(spinner.Dot))}
+}
+
+func (m statsModel) Init() tea.Cmd {
+	if m.store == nil {
+		return nil
+	}
+	return tea.Batch(m.spinner.Tick, loadStats(m.store))
+}
+
+func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case statsMsg:
+		st := stats(msg)
+		m.stats = &st
+	case spinner.TickMsg:
+		if m.stats != nil {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Close):
+			return m, popView
+		}
+	}
+	return m, nil
+}
+
+func (m statsModel) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(tr("Stats")) + "\n")
+	switch {
+	case m.store == nil:
+		b.WriteString(tr("Stats aren't kept without a database.") + "\n")
+	case m.stats == nil:
+		b.WriteString(m.spinner.View() + " " + tr("Counting up your games...") + "\n")
+	default:
+		st := m.stats
+		row := func(label string, v any) {
+			b.WriteString(fmt.Sprintf("%-24s %v\n", tr(label), v))
+		}
+		row("Games played", st.played)
+		row("Puzzles solved", st.solved)
+		row("Current streak (days)", st.streak)
+		averages := func(st *stats) {
+			if st.solved > 0 {
+				row("Incorrect per solve", fmt.Sprintf("%.1f", float64(st.incorrect)/float64(st.solved)))
+				row("Hints per solve", fmt.Sprintf("%.1f", float64(st.hints)/float64(st.solved)))
+			}
+			if st.timed > 0 {
+				row("Average solve time", formatElapsed(st.solveTime/time.Duration(st.timed)))
+			}
+		}
+		averages(st)
+		if ex := st.expert; ex.played > 0 {
+			b.WriteString("\n" + headerStyle.Render(tr("Expert mode")) + "\n")
+			row("Games played", ex.played)
+			row("Puzzles solved", ex.solved)
+			averages(ex)
+		}
+	}
+	back := keys.Close
+	back.SetHelp("esc", "back")
+	b.WriteString("\n" + noticeStyle.Render(helpLine(back)))
+	return b.String()
+}
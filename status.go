@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// statusReport is the summary of today's puzzle written by brack status.
+type statusReport struct {
+	Date           string `json:"date"`
+	Status         string `json:"status"` // not played, in progress, solved or gave up
+	ElapsedSeconds int64  `json:"elapsed_seconds"`
+	Incorrect      int    `json:"incorrect"`
+	Streak         int    `json:"streak"`
+}
+
+// runStatus writes a summary of today's puzzle and the streak, for
+// scripts and shell prompts, as text or JSON. It only reads the
+// database, so it never fetches anything.
+func runStatus(w io.Writer, s *store, now time.Time, asJSON bool) error {
+	r := statusReport{
+		Date:   now.Format(time.DateOnly),
+		Status: "not played",
+		Streak: currentStreak(s, now),
+	}
+	if gs, ok := s.game(r.Date); ok {
+		r.Status = gameStatus(gs)
+		r.ElapsedSeconds = gs.ElapsedSeconds
+		r.Incorrect = gs.Incorrect
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	fmt.Fprintf(w, "%s: %s", r.Date, r.Status)
+	if r.Status != "not played" {
+		fmt.Fprintf(w, " (%s, %d incorrect)", formatElapsed(time.Duration(r.ElapsedSeconds)*time.Second), r.Incorrect)
+	}
+	fmt.Fprintf(w, "\nStreak: %d days\n", r.Streak)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// statusFields are the fields a status line template can show, e.g.
+// "{correct}/{total} | errors {incorrect} | {elapsed}".
+var statusFields = map[string]func(m model) string{
+	"correct":   func(m model) string { return strconv.Itoa(m.correct) },
+	"incorrect": func(m model) string { return strconv.Itoa(m.incorrect) },
+	"total":     func(m model) string { return strconv.Itoa(len(m.data.Solutions)) },
+	"remaining": func(m model) string { return strconv.Itoa(len(m.data.Solutions) - m.correct) },
+	"chars":     func(m model) string { return strconv.Itoa(m.chars) },
+	"elapsed":   func(m model) string { return formatElapsed(m.elapsedNow()) },
+	"date":      func(m model) string { return m.data.PuzzleDate },
+	"assisted": func(m model) string {
+		if m.assisted {
+			return tr("🛟 assisted")
+		}
+		return ""
+	},
+	"expert": func(m model) string {
+		if m.expert {
+			return tr("🎓 expert")
+		}
+		return ""
+	},
+}
+
+// statusPart is a piece of a status line template: literal text, or
+// a field.
+type statusPart struct {
+	text  string
+	field string
+}
+
+// parseStatusLine parses a status line template. Fields are in braces,
+// and "{{" and "}}" are literal braces.
+func parseStatusLine(tmpl string) ([]statusPart, error) {
+	var parts []statusPart
+	var text strings.Builder
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		switch {
+		case c == '{' && strings.HasPrefix(tmpl[i:], "{{"), c == '}' && strings.HasPrefix(tmpl[i:], "}}"):
+			text.WriteByte(c)
+			i++
+		case c == '{':
+			end := strings.IndexByte(tmpl[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed { at column %d", i+1)
+			}
+			name := strings.TrimSpace(tmpl[i+1 : i+end])
+			if _, ok := statusFields[name]; !ok {
+				return nil, fmt.Errorf("unknown field {%s} (expected one of %s)", name, fieldNames())
+			}
+			if text.Len() > 0 {
+				parts = append(parts, statusPart{text: text.String()})
+				text.Reset()
+			}
+			parts = append(parts, statusPart{field: name})
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("unopened } at column %d", i+1)
+		default:
+			text.WriteByte(c)
+		}
+	}
+	if text.Len() > 0 {
+		parts = append(parts, statusPart{text: text.String()})
+	}
+	return parts, nil
+}
+
+// fieldNames lists the status line fields, for error messages.
+func fieldNames() string {
+	names := make([]string, 0, len(statusFields))
+	for name := range statusFields {
+		names = append(names, "{"+name+"}")
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// statusLine renders the configured status line template.
+func (m model) statusLine() string {
+	var b strings.Builder
+	for _, p := range m.statusTmpl {
+		if p.field != "" {
+			b.WriteString(statusFields[p.field](m))
+		} else {
+			b.WriteString(p.text)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
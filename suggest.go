@@ -0,0 +1,56 @@
+package main
+
+import (
+	"time"
+)
+
+// suggestDays is how far back to look for a puzzle to suggest next.
+const suggestDays = 30
+
+// nextPuzzle suggests a puzzle to play after this one (see
+// suggestPuzzle).
+func (m model) nextPuzzle() (date string, unfinished, ok bool) {
+	if m.store == nil {
+		return "", false, false
+	}
+	return suggestPuzzle(m.store, m.data.PuzzleDate)
+}
+
+// suggestPuzzle suggests a puzzle to play, other than the one for skip:
+// the latest one left unfinished, or else the latest one not played
+// yet, in the last month. unfinished says which it is.
+func suggestPuzzle(s *store, skip string) (date string, unfinished, ok bool) {
+	var unplayed string
+	day := time.Now()
+	for range suggestDays {
+		d := day.Format(time.DateOnly)
+		day = day.AddDate(0, 0, -1)
+		if d == skip {
+			continue
+		}
+		gs, played := s.game(d)
+		switch {
+		case played && !gs.Done:
+			return d, true, true
+		case !played && unplayed == "":
+			unplayed = d
+		}
+	}
+	return unplayed, false, unplayed != ""
+}
+
+// suggestion describes the suggested next puzzle, with the key to play
+// it.
+func (m model) suggestion(date string, unfinished bool) string {
+	k := keys.Next.Help().Key
+	if unfinished {
+		return tr("👉 You haven't finished the puzzle from %s — press %s to pick it back up", date, k)
+	}
+	switch date {
+	case today():
+		return tr("👉 Today's puzzle is unplayed — press %s to play it", k)
+	case time.Now().AddDate(0, 0, -1).Format(time.DateOnly):
+		return tr("👉 Yesterday's puzzle is unplayed — press %s to play it", k)
+	}
+	return tr("👉 The puzzle from %s is unplayed — press %s to play it", date, k)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var _ tea.Model = session{}
+
+// openTabMsg is the result of loading a puzzle to open in a new tab.
+// Outside of a session, it switches puzzles like puzzleLoadedMsg.
+type openTabMsg puzzleLoadedMsg
+
+// loadTabCmd loads the puzzle for a date to open in a new tab.
+func loadTabCmd(s *store, d time.Time) tea.Cmd {
+	load := loadPuzzleCmd(s, d)
+	return func() tea.Msg {
+		return openTabMsg(load().(puzzleLoadedMsg))
+	}
+}
+
+// session holds the games open as tabs, for playing several puzzles
+// in one run. Only the current tab gets messages, and the others keep
+// their timers stopped.
+type session struct {
+	tabs []model
+	cur  int
+	w, h int
+}
+
+func newSession(m model) session {
+	return session{tabs: []model{m}}
+}
+
+func (s session) Init() tea.Cmd {
+	return s.tabs[s.cur].Init()
+}
+
+func (s session) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.w, s.h = msg.Width, msg.Height
+		s.resize()
+		return s, nil
+
+	case openTabMsg:
+		if msg.err != nil {
+			break
+		}
+		return s.open(msg.puzzle)
+
+	case profileSwitchedMsg:
+		if msg.err != nil {
+			break
+		}
+
+		// The other tabs belong to the old profile, so save and close
+		// them
+		for i := range s.tabs {
+			if i != s.cur {
+				s.tabs[i].save()
+			}
+		}
+		t, cmd := s.tabs[s.cur].Update(msg)
+		s.tabs, s.cur = []model{t.(model)}, 0
+		s.resize()
+		return s, cmd
+
+	case tea.KeyMsg:
+		// Digits type into the answer (or choose a clue in select
+		// mode), so they only switch tabs without alt when nothing's
+		// been typed. Notes and dialogs take every key.
+		t := s.tabs[s.cur]
+		if key.Matches(msg, keys.SwitchTab) && !t.palette && !t.launching && !t.calFocus && !t.noting && t.dialog == nil &&
+			(msg.Alt || t.done || t.txtin.Value() == "" && !t.selectMode) {
+			return s.activate(int(msg.Runes[0] - '1'))
+		}
+	}
+
+	var cmd tea.Cmd
+	t, cmd := s.tabs[s.cur].Update(msg)
+	s.tabs[s.cur] = t.(model)
+	return s, cmd
+}
+
+// open opens a puzzle in a new tab, or switches to its tab if it's
+// already open.
+func (s session) open(p puzzledata) (tea.Model, tea.Cmd) {
+	for i, t := range s.tabs {
+		if t.data.PuzzleDate == p.PuzzleDate {
+			return s.activate(i)
+		}
+	}
+
+	cur := s.tabs[s.cur]
+	cur.toast = ""
+	cur.stopTimer()
+	n, cmd := cur.openPuzzle(p, true)
+	s.tabs[s.cur] = cur
+	s.tabs = append(s.tabs, n)
+	s.cur = len(s.tabs) - 1
+	s.resize()
+	return s, cmd
+}
+
+// activate switches to the i'th tab, saving the current one and
+// moving the timer over.
+func (s session) activate(i int) (tea.Model, tea.Cmd) {
+	if i < 0 || i >= len(s.tabs) || i == s.cur {
+		return s, nil
+	}
+	old := &s.tabs[s.cur]
+	old.stopTimer()
+	old.save()
+
+	t := &s.tabs[i]
+	t.toast = ""
+	t.blurred = old.blurred
+	t.bc = old.bc
+	t.team, t.player = old.team, old.player
+	t.setStreamer(old.streamer)
+	s.cur = i
+	s.resize()
+	t.publish()
+
+	// Retire the old tick loop
+	t.tickID = old.tickID + 1
+	if t.countingDown() {
+		return s, t.tick()
+	}
+	if t.done || t.paused || t.blurred {
+		return s, nil
+	}
+	return s, t.startTimer()
+}
+
+// resize sizes the current tab to the window, less the tab bar.
+func (s *session) resize() {
+	if s.w == 0 {
+		return
+	}
+	h := s.h
+	if len(s.tabs) > 1 {
+		h -= 2
+	}
+	t, _ := s.tabs[s.cur].Update(tea.WindowSizeMsg{Width: s.w, Height: h})
+	s.tabs[s.cur] = t.(model)
+}
+
+func (s session) View() string {
+	if len(s.tabs) == 1 {
+		return s.tabs[0].View()
+	}
+	return s.tabBar() + "\n\n" + s.tabs[s.cur].View()
+}
+
+// tabBar lists the open tabs, with the current one highlighted.
+func (s session) tabBar() string {
+	var parts []string
+	for i, t := range s.tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, t.data.PuzzleDate)
+		if t.done {
+			label = fmt.Sprintf(" %d %s ✓ ", i+1, t.data.PuzzleDate)
+		}
+		if i == s.cur {
+			label = activeStyle.Render(label)
+		} else {
+			label = noticeStyle.Render(label)
+		}
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, "│")
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+// Digits typed into a note on the results screen are the note's, not
+// tab switches.
+func TestSwitchTabWhileNoting(t *testing.T) {
+	p, err := loadDemoPuzzle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := openStore(memoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := defaultConfig()
+	cfg.CheckForUpdates = false
+	m := newModel(p, s, cfg)
+	m.stopTimer()
+	other := p
+	other.PuzzleDate = "2025-01-02"
+
+	d := newDriver(newSession(m)).size(80, 24)
+	d.send(openTabMsg{puzzle: other})
+	if _, err := d.keys("alt+1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, answer := range []string{"big", "bracket city", "terminal"} {
+		d.typ(answer)
+		if _, err := d.keys("enter"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.keys("e"); err != nil {
+		t.Fatal(err)
+	}
+	d.typ("2 tries")
+	if _, err := d.keys("enter"); err != nil {
+		t.Fatal(err)
+	}
+
+	if cur := d.m.(session).cur; cur != 0 {
+		t.Errorf("typing the note switched to tab %d", cur+1)
+	}
+	if note := s.note(p.PuzzleDate); note != "2 tries" {
+		t.Errorf("the note is %q, want %q", note, "2 tries")
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// teamMsg is a message in a team session, sent as a line of JSON over
+// TCP. Players send a "hello" with their name on joining, then their
+// "guess"es. The host sends the game's "state" after every change, and
+// an "error" to a player whose guess can't be made.
+type teamMsg struct {
+	Type    string       `json:"type"`
+	Name    string       `json:"name,omitempty"`
+	Answer  string       `json:"answer,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Game    *gameReport  `json:"game,omitempty"`
+	Last    *gameEvent   `json:"last,omitempty"` // the latest guess
+	Players []teamPlayer `json:"players,omitempty"`
+}
+
+// teamPlayer is what a player has contributed to a team game.
+type teamPlayer struct {
+	Name      string `json:"name"`
+	Correct   int    `json:"correct"`
+	Incorrect int    `json:"incorrect"`
+}
+
+// teamPlayers tallies who made the guesses in a game's events, in the
+// order they first guessed. Games that weren't played as a team have
+// no one.
+func teamPlayers(events []gameEvent) []teamPlayer {
+	var ps []teamPlayer
+	for _, e := range events {
+		if e.By == "" || e.Kind != "correct" && e.Kind != "incorrect" {
+			continue
+		}
+		i := slices.IndexFunc(ps, func(p teamPlayer) bool { return p.Name == e.By })
+		if i < 0 {
+			i = len(ps)
+			ps = append(ps, teamPlayer{Name: e.By})
+		}
+		if e.Kind == "correct" {
+			ps[i].Correct++
+		} else {
+			ps[i].Incorrect++
+		}
+	}
+	return ps
+}
+
+// teamView lists who contributed what to a team game, or nothing for
+// a game played alone.
+func teamView(ps []teamPlayer) string {
+	if len(ps) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(tr("👥 Who solved what:"))
+	for _, p := range ps {
+		fmt.Fprintf(&b, "\n  %s  ✅ %d ❌ %d", p.Name, p.Correct, p.Incorrect)
+	}
+	return b.String()
+}
+
+// teamName tidies up the name a player joins with.
+func teamName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if r := []rune(name); len(r) > 20 {
+		name = string(r[:20])
+	}
+	return name
+}
+
+// defaultTeamName is the name to play under in a team session, unless
+// another is given.
+func defaultTeamName() string {
+	for _, v := range []string{"USER", "USERNAME"} {
+		if name := teamName(os.Getenv(v)); name != "" {
+			return name
+		}
+	}
+	return "player"
+}
+
+// teamGuessMsg is a guess from a player who joined, for the hosted game
+// to make (see model.teamGuess).
+type teamGuessMsg struct {
+	answer string
+	from   *teamConn
+}
+
+// teamJoinMsg tells the host that a player joined or left.
+type teamJoinMsg struct {
+	name string
+	left bool
+}
+
+// teamConn is a player's connection to the host. Messages are queued,
+// so the game never waits on a slow player, and a player who falls too
+// far behind misses some states, as the next one replaces them anyway.
+type teamConn struct {
+	name string
+	conn net.Conn
+	out  chan teamMsg
+	gone chan struct{} // closed when the player leaves
+}
+
+func (c *teamConn) send(msg teamMsg) {
+	select {
+	case c.out <- msg:
+	default:
+		slog.Warn("dropping team message for a slow player", "name", c.name, "type", msg.Type)
+	}
+}
+
+// tell sends a player an error.
+func (c *teamConn) tell(msg string) {
+	c.send(teamMsg{Type: "error", Error: msg})
+}
+
+func (c *teamConn) write() {
+	enc := json.NewEncoder(c.conn)
+	for {
+		select {
+		case <-c.gone:
+			return
+		case msg := <-c.out:
+			if err := enc.Encode(msg); err != nil {
+				slog.Debug("failed to write to player", "name", c.name, "err", err)
+				c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// teamHost serves a team session: players join over TCP, and the
+// host's game makes their guesses and sends them its state.
+type teamHost struct {
+	name string // the host's own name
+	date string // the puzzle being played
+	addr string
+	msgs chan tea.Msg
+
+	mu    sync.Mutex
+	conns map[*teamConn]bool
+	last  teamMsg
+}
+
+// startTeamHost starts serving a team session on addr (e.g. ":7000")
+// in the background. The returned function ends it.
+func startTeamHost(addr, name string) (*teamHost, func() error, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	h := &teamHost{
+		name:  name,
+		addr:  ln.Addr().String(),
+		msgs:  make(chan tea.Msg),
+		conns: make(map[*teamConn]bool),
+	}
+	go h.accept(ln)
+	slog.Info("hosting team session", "addr", h.addr)
+	return h, func() error {
+		err := ln.Close()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		for c := range h.conns {
+			c.conn.Close()
+		}
+		return err
+	}, nil
+}
+
+// joinAddr is the address players can join at, with this machine's
+// name when listening on every interface.
+func (h *teamHost) joinAddr() string {
+	host, port, err := net.SplitHostPort(h.addr)
+	if err != nil {
+		return h.addr
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host, _ = os.Hostname()
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// players returns how many players have joined.
+func (h *teamHost) players() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// listen waits for the next guess or player joining. Whatever handles
+// the message listens again, so there's only ever one waiting.
+func (h *teamHost) listen() tea.Cmd {
+	return func() tea.Msg {
+		return <-h.msgs
+	}
+}
+
+// publish sends the game's state to every player.
+func (h *teamHost) publish(msg teamMsg) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = msg
+	for c := range h.conns {
+		c.send(msg)
+	}
+}
+
+func (h *teamHost) accept(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Error("team session stopped accepting players", "err", err)
+			}
+			return
+		}
+		go h.serve(conn)
+	}
+}
+
+// serve talks to a player until they leave.
+func (h *teamHost) serve(conn net.Conn) {
+	defer conn.Close()
+	c := &teamConn{conn: conn, out: make(chan teamMsg, 16), gone: make(chan struct{})}
+
+	// The first message says who's joining. Until they have, errors are
+	// written straight away, as the connection is closed after.
+	refuse := func(msg string) {
+		json.NewEncoder(conn).Encode(teamMsg{Type: "error", Error: msg})
+	}
+	sc := bufio.NewScanner(conn)
+	var hello teamMsg
+	if !sc.Scan() || json.Unmarshal(sc.Bytes(), &hello) != nil || hello.Type != "hello" {
+		refuse("expected a hello")
+		return
+	}
+	c.name = teamName(hello.Name)
+	if err := h.add(c); err != nil {
+		refuse(err.Error())
+		return
+	}
+	go c.write()
+	defer close(c.gone)
+
+	h.msgs <- teamJoinMsg{name: c.name}
+	defer func() {
+		h.mu.Lock()
+		delete(h.conns, c)
+		h.mu.Unlock()
+		h.msgs <- teamJoinMsg{name: c.name, left: true}
+	}()
+
+	for sc.Scan() {
+		var msg teamMsg
+		if json.Unmarshal(sc.Bytes(), &msg) != nil || msg.Type != "guess" {
+			c.tell("expected a guess")
+			continue
+		}
+		if answer := strings.TrimSpace(msg.Answer); answer != "" {
+			h.msgs <- teamGuessMsg{answer: answer, from: c}
+		}
+	}
+}
+
+// add lets a player join, under a name no one else has, sending them
+// the game so far.
+func (h *teamHost) add(c *teamConn) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c.name == "" {
+		return errors.New("a name is needed to join")
+	}
+	taken := strings.EqualFold(c.name, h.name)
+	for o := range h.conns {
+		taken = taken || strings.EqualFold(c.name, o.name)
+	}
+	if taken {
+		return fmt.Errorf("someone called %s has already joined, try another --name", c.name)
+	}
+	h.conns[c] = true
+	if h.last.Type != "" {
+		c.send(h.last)
+	}
+	return nil
+}
+
+// teamState is the game's state, as sent to the players.
+func (m model) teamState() teamMsg {
+	g := m.report()
+	msg := teamMsg{Type: "state", Game: &g, Players: teamPlayers(m.events)}
+	for i := len(m.events) - 1; i >= 0; i-- {
+		if e := m.events[i]; e.Kind == "correct" || e.Kind == "incorrect" {
+			msg.Last = &e
+			break
+		}
+	}
+	return msg
+}
+
+// teamGuess makes a guess from a player who joined, as the host would,
+// telling them if it can't be made.
+func (m model) teamGuess(msg teamGuessMsg) (model, tea.Cmd) {
+	switch {
+	case m.data.PuzzleDate != m.team.date:
+		msg.from.tell(tr("The host is on another puzzle"))
+		return m, nil
+	case m.done:
+		msg.from.tell(tr("The puzzle is finished"))
+		return m, nil
+	case m.lockedBy != "":
+		msg.from.tell(tr("The puzzle is read-only for the host"))
+		return m, nil
+	case m.paused:
+		msg.from.tell(tr("The host has paused the game"))
+		return m, nil
+	case m.cooldownLeft() > 0:
+		msg.from.tell(tr("Cooling down after a wrong guess, wait %s", formatElapsed(m.cooldownLeft())))
+		return m, nil
+	}
+
+	// Check it against every clue, not one the host has chosen in
+	// select mode, which is still chosen after
+	before, wrong := m.correct, m.incorrect
+	target, toast := m.target, m.toast
+	m.target, m.player = "", msg.from.name
+	n, cmd := m.submit(msg.answer)
+	n.player = m.team.name
+	if target != "" && slices.Contains(n.activeClues(), target) {
+		n.target = target
+	}
+
+	switch {
+	case n.correct > before:
+		n.toast = tr("👥 %s got %q", msg.from.name, msg.answer)
+	case n.incorrect > wrong:
+		n.toast = tr("👥 %s guessed %q, which is wrong", msg.from.name, msg.answer)
+	default:
+		// Turned away, in expert mode
+		msg.from.tell(ansi.Strip(n.toast))
+		n.toast = toast
+	}
+	return n, cmd
+}
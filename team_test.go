@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// told returns the error the host last sent a player, if any.
+func told(c *teamConn) string {
+	var last string
+	for {
+		select {
+		case msg := <-c.out:
+			if msg.Type == "error" {
+				last = msg.Error
+			}
+		default:
+			return last
+		}
+	}
+}
+
+func TestTeamGuess(t *testing.T) {
+	m := newTestGame(t).m.(model)
+	m.team = &teamHost{name: "host", date: m.data.PuzzleDate, conns: make(map[*teamConn]bool)}
+	ana := &teamConn{name: "ana", out: make(chan teamMsg, 10), gone: make(chan struct{})}
+
+	m, _ = m.teamGuess(teamGuessMsg{answer: "big", from: ana})
+	if m.correct != 1 {
+		t.Fatalf("a right guess from a player wasn't made: %d correct", m.correct)
+	}
+	if !strings.Contains(m.toast, "ana") {
+		t.Errorf("the host isn't told who got it: %q", m.toast)
+	}
+	if m.player != "host" {
+		t.Errorf("the host's guesses are now made as %q", m.player)
+	}
+	if e := m.events[len(m.events)-1]; e.By != "ana" || e.Kind != "correct" {
+		t.Errorf("the guess is logged as %+v, want a correct one by ana", e)
+	}
+
+	m, _ = m.teamGuess(teamGuessMsg{answer: "small", from: ana})
+	if m.incorrect != 1 || !strings.Contains(m.toast, "wrong") {
+		t.Errorf("a wrong guess: %d incorrect, toast %q", m.incorrect, m.toast)
+	}
+	if ps := teamPlayers(m.events); len(ps) != 1 || ps[0] != (teamPlayer{Name: "ana", Correct: 1, Incorrect: 1}) {
+		t.Errorf("the players are %+v", ps)
+	}
+
+	// Guesses that can't be made are turned away, and the player told
+	for _, tc := range []struct {
+		name  string
+		setup func(m *model)
+		want  string
+	}{
+		{"another puzzle", func(m *model) { m.team = &teamHost{name: "host", date: "2000-01-01", conns: make(map[*teamConn]bool)} }, "another puzzle"},
+		{"paused", func(m *model) { m.paused = true }, "paused"},
+		{"read-only", func(m *model) { m.lockedBy = "pid 1 on elsewhere" }, "read-only"},
+		{"finished", func(m *model) { m.done = true }, "finished"},
+	} {
+		before := m
+		tc.setup(&before)
+		after, _ := before.teamGuess(teamGuessMsg{answer: "terminal", from: ana})
+		if after.correct != m.correct {
+			t.Errorf("%s: the guess was made", tc.name)
+		}
+		if msg := told(ana); !strings.Contains(msg, tc.want) {
+			t.Errorf("%s: the player was told %q, want it to mention %q", tc.name, msg, tc.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tickMsg redraws the timer each second. Ticks from before the timer
+// was last (re)started are ignored, so only one tick loop is running.
+type tickMsg struct {
+	id int
+}
+
+func (m model) tick() tea.Cmd {
+	id := m.tickID
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return tickMsg{id: id}
+	})
+}
+
+// running reports whether the solve timer is running.
+func (m model) running() bool {
+	return !m.resumed.IsZero()
+}
+
+// elapsedNow returns the total time spent solving so far.
+func (m model) elapsedNow() time.Duration {
+	if !m.running() {
+		return m.elapsed
+	}
+	return m.elapsed + time.Since(m.resumed)
+}
+
+// startTimer (re)starts the solve timer.
+func (m *model) startTimer() tea.Cmd {
+	m.resumed = time.Now()
+	m.tickID++
+	return m.tick()
+}
+
+// stopTimer stops the solve timer, keeping the time so far.
+func (m *model) stopTimer() {
+	m.elapsed = m.elapsedNow()
+	m.resumed = time.Time{}
+}
+
+// formatElapsed formats a duration as m:ss, or h:mm:ss.
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	h, mins, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, mins, s)
+	}
+	return fmt.Sprintf("%d:%02d", mins, s)
+}
+
+// pausedView hides the puzzle while the game is paused.
+func (m model) pausedView() string {
+	return lipgloss.JoinVertical(lipgloss.Left,
+		headerStyle.Render("[ Bracket City | "+m.data.PuzzleDate+" ]"),
+		"⏱️ "+formatElapsed(m.elapsedNow()),
+		"---",
+		"",
+		tr("⏸️ Paused"),
+		"",
+		"---",
+		noticeStyle.Render(tr("%s to resume", keys.Pause.Help().Key)),
+	)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// timingBarWidth is how long the bar of the slowest clue is.
+const timingBarWidth = 20
+
+// clueTime is how long a clue took to solve: the solve time between
+// the answer before it and its own.
+type clueTime struct {
+	Clue   string
+	Answer string
+	Took   time.Duration
+}
+
+// clueTimes works out how long each clue took from a game's events, in
+// the order they were solved. Games from before events were logged
+// have none.
+func clueTimes(events []gameEvent) []clueTime {
+	var out []clueTime
+	var prev int64
+	for _, e := range events {
+		if e.Kind != "correct" {
+			continue
+		}
+		out = append(out, clueTime{
+			Clue:   e.Clue,
+			Answer: e.Guess,
+			Took:   time.Duration(max(e.Elapsed-prev, 0)) * time.Millisecond,
+		})
+		prev = e.Elapsed
+	}
+	return out
+}
+
+// timingStyle shades a clue's bar by how long it took compared to the
+// slowest: green for the quick ones, through yellow, to red.
+func timingStyle(took, slowest time.Duration) lipgloss.Style {
+	switch {
+	case took*3 >= slowest*2:
+		return lipgloss.NewStyle().Foreground(colorRed)
+	case took*3 >= slowest:
+		return lipgloss.NewStyle().Foreground(colorYellow)
+	}
+	return lipgloss.NewStyle().Foreground(colorGreens[0])
+}
+
+// timingView is a heatmap of how long each clue took, for the results
+// screen, to show where the game got stuck.
+func (m model) timingView() string {
+	times := clueTimes(m.events)
+	if len(times) == 0 {
+		return noticeStyle.Render(tr("No clue timings were recorded for this game."))
+	}
+	var slowest time.Duration
+	for _, t := range times {
+		slowest = max(slowest, t.Took)
+	}
+
+	var b strings.Builder
+	b.WriteString(tr("⏱️ How long each clue took, in the order solved:"))
+	for _, t := range times {
+		n := timingBarWidth
+		if slowest > 0 {
+			n = max(int(int64(timingBarWidth)*int64(t.Took)/int64(slowest)), 1)
+		}
+		bar := timingStyle(t.Took, slowest).Render(strings.Repeat("█", n)) + strings.Repeat(" ", timingBarWidth-n)
+		line := fmt.Sprintf("%s %7s  [%s] → %s", bar, formatElapsed(t.Took), t.Clue, t.Answer)
+		b.WriteString("\n" + ansi.Truncate(line, m.bodyWidth(), "…"))
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// today returns today's date, the way puzzles are keyed.
+func today() string {
+	return time.Now().Format(time.DateOnly)
+}
+
+// nextPuzzleAt returns when the puzzle after today's comes out: the
+// next midnight, in the local time zone the puzzles are keyed by. It's
+// not always 24 hours away, across a daylight saving change.
+func nextPuzzleAt(now time.Time) time.Time {
+	y, mo, d := now.Date()
+	return time.Date(y, mo, d+1, 0, 0, 0, 0, now.Location())
+}
+
+// countingDown reports whether the results screen counts down to the
+// next puzzle, which it does once today's is finished.
+func (m model) countingDown() bool {
+	return m.done && !m.warmup && m.data.PuzzleDate == today()
+}
+
+// countdownView counts down to the next puzzle. The timer keeps
+// ticking on the results screen to redraw it.
+func (m model) countdownView() string {
+	now := time.Now()
+	return tr("⏳ The next puzzle is out in %s", formatElapsed(nextPuzzleAt(now).Sub(now)))
+}
+
+// todayHelp is the help for the today shortcut, saying whether today's
+// puzzle is already solved. While playing it needs alt, since "t"
+// could be part of an answer.
+func todayHelp(s *store, playing bool) key.Binding {
+	b := keys.Today
+	k := "t"
+	if playing {
+		k = "alt+t"
+	}
+	b.SetHelp(k, "today's puzzle")
+	if s != nil {
+		if gs, ok := s.game(today()); ok && gs.Done {
+			b.SetHelp(k, "today's puzzle (solved)")
+		}
+	}
+	return b
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var _ tea.Model = trendsModel{}
+
+// trendsRolling is how many days each point on the trend charts is
+// averaged over.
+const trendsRolling = 90
+
+// trendsChartH is how many lines tall the chart is.
+const trendsChartH = 10
+
+// trendPoint is a solved game, as charted.
+type trendPoint struct {
+	at        time.Time
+	seconds   int64
+	chars     int
+	incorrect int
+}
+
+// trendMetric is something the trends screen charts, with how to get it
+// from a solve (if the solve has it) and show it.
+type trendMetric struct {
+	name   string
+	value  func(p trendPoint) (float64, bool)
+	format func(v float64) string
+}
+
+var trendMetrics = []trendMetric{
+	{
+		name: "solve time",
+		value: func(p trendPoint) (float64, bool) {
+			return float64(p.seconds), p.seconds > 0
+		},
+		format: func(v float64) string { return formatElapsed(time.Duration(v * float64(time.Second))) },
+	},
+	{
+		name:   "keystrokes",
+		value:  func(p trendPoint) (float64, bool) { return float64(p.chars), true },
+		format: func(v float64) string { return fmt.Sprintf("%.0f", v) },
+	},
+	{
+		name:   "incorrect guesses",
+		value:  func(p trendPoint) (float64, bool) { return float64(p.incorrect), true },
+		format: func(v float64) string { return fmt.Sprintf("%.1f", v) },
+	},
+}
+
+// trendWindows are the spans of time the trends screen can chart, in
+// days. All time (0) goes back to the first solve.
+var trendWindows = []struct {
+	name string
+	days int
+}{
+	{"3 months", 91},
+	{"6 months", 182},
+	{"1 year", 365},
+	{"all time", 0},
+}
+
+// trendsMsg is the result of loading the solves to chart.
+type trendsMsg []trendPoint
+
+// loadTrends gathers the solved games in the background, oldest first.
+// Like the stats, games in expert mode aren't counted.
+func loadTrends(s *store) tea.Cmd {
+	return func() tea.Msg {
+		var points []trendPoint
+		for _, gs := range s.allGames() {
+			if _, err := time.Parse(time.DateOnly, gs.Date); err != nil || !gs.Done || gs.GaveUp || gs.Expert {
+				continue
+			}
+			points = append(points, trendPoint{at: solvedAt(gs), seconds: gs.ElapsedSeconds, chars: gs.Chars, incorrect: gs.Incorrect})
+		}
+		slices.SortFunc(points, func(a, b trendPoint) int { return a.at.Compare(b.at) })
+		return trendsMsg(points)
+	}
+}
+
+// trendsModel charts rolling averages of the player's solves over
+// time, over the game. Keys switch between the metrics and how far
+// back the chart goes.
+type trendsModel struct {
+	store   *store
+	today   time.Time
+	points  []trendPoint
+	loaded  bool
+	spinner spinner.Model
+	w       int
+
+	// metric and window index trendMetrics and trendWindows.
+	metric, window int
+}
+
+func newTrendsModel(s *store, now time.Time) trendsModel {
+	return trendsModel{
+		store:   s,
+		today:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local),
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot)),
+		window:  2,
+	}
+}
+
+func (m trendsModel) Init() tea.Cmd {
+	if m.store == nil {
+		return nil
+	}
+	return tea.Batch(m.spinner.Tick, loadTrends(m.store))
+}
+
+func (m trendsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.w = msg.Width
+	case trendsMsg:
+		m.points, m.loaded = msg, true
+	case spinner.TickMsg:
+		if m.loaded {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Close):
+			return m, popView
+		case key.Matches(msg, keys.Metric):
+			m.metric = (m.metric + 1) % len(trendMetrics)
+		case key.Matches(msg, keys.Window):
+			m.window = (m.window + 1) % len(trendWindows)
+		}
+	}
+	return m, nil
+}
+
+// rolling is the average of the metric over the solves in the
+// trendsRolling days up to the end of day, if there were any.
+func (m trendsModel) rolling(day time.Time) (float64, bool) {
+	from, to := day.AddDate(0, 0, 1-trendsRolling), day.AddDate(0, 0, 1)
+	var sum float64
+	var n int
+	for _, p := range m.points {
+		if p.at.Before(from) || !p.at.Before(to) {
+			continue
+		}
+		if v, ok := trendMetrics[m.metric].value(p); ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// chart draws a column per day sampled from the window, each the
+// rolling average up to that day, with eighth blocks for the tops.
+// Days without any solves are left blank.
+func (m trendsModel) chart(start time.Time, days, width int) string {
+	metric := trendMetrics[m.metric]
+	cols := min(width, days)
+	values := make([]float64, cols)
+	var top float64
+	for i := range values {
+		v, ok := m.rolling(start.AddDate(0, 0, (i+1)*days/cols-1))
+		if !ok {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i], top = v, max(top, v)
+	}
+	if top == 0 {
+		return noticeStyle.Render(tr("Nothing to chart for %s in this window.", tr(metric.name)))
+	}
+
+	labels := []string{metric.format(top), metric.format(top / 2), metric.format(0)}
+	labelW := 0
+	for _, l := range labels {
+		labelW = max(labelW, len(l))
+	}
+	var b strings.Builder
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	for row := range trendsChartH {
+		label := ""
+		switch row {
+		case 0:
+			label = labels[0]
+		case trendsChartH / 2:
+			label = labels[1]
+		case trendsChartH - 1:
+			label = labels[2]
+		}
+		fmt.Fprintf(&b, "%*s │", labelW, label)
+		floor := float64((trendsChartH - 1 - row) * 8)
+		for _, v := range values {
+			eighths := int(math.Round(v/top*trendsChartH*8 - floor))
+			if math.IsNaN(v) || eighths <= 0 {
+				b.WriteString(" ")
+				continue
+			}
+			b.WriteRune(blocks[min(eighths, 8)-1])
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat(" ", labelW+1) + "└" + strings.Repeat("─", cols) + "\n")
+	first, last := start.Format("Jan 2, 2006"), start.AddDate(0, 0, days-1).Format("Jan 2, 2006")
+	b.WriteString(strings.Repeat(" ", labelW+2) + first + strings.Repeat(" ", max(cols-len(first)-len(last), 1)) + last)
+	return b.String()
+}
+
+// toggles shows the choices, with the current one highlighted.
+func toggles(names []string, cur int) string {
+	var parts []string
+	for i, name := range names {
+		if i == cur {
+			parts = append(parts, activeStyle.Render(" "+tr(name)+" "))
+		} else {
+			parts = append(parts, noticeStyle.Render(" "+tr(name)+" "))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (m trendsModel) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(tr("Trends")) + "\n")
+	switch {
+	case m.store == nil:
+		b.WriteString(tr("Stats aren't kept without a database.") + "\n")
+	case !m.loaded:
+		b.WriteString(m.spinner.View() + " " + tr("Counting up your games...") + "\n")
+	case len(m.points) == 0:
+		b.WriteString(tr("Solve a puzzle or two to see your trends.") + "\n")
+	default:
+		var metrics, windows []string
+		for _, mt := range trendMetrics {
+			metrics = append(metrics, mt.name)
+		}
+		for _, w := range trendWindows {
+			windows = append(windows, w.name)
+		}
+		b.WriteString(toggles(metrics, m.metric) + "\n")
+		b.WriteString(toggles(windows, m.window) + "\n\n")
+
+		days := trendWindows[m.window].days
+		if days == 0 {
+			first := m.points[0].at
+			start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.Local)
+			days = int(m.today.Sub(start).Round(24*time.Hour).Hours()/24) + 1
+		}
+		start := m.today.AddDate(0, 0, 1-days)
+		width := maxBodyWidth
+		if m.w > 0 {
+			width = min(m.w, width)
+		}
+		b.WriteString(tr("%d-day average of %s:", trendsRolling, tr(trendMetrics[m.metric].name)) + "\n")
+		// Leave room for the axis labels
+		b.WriteString(m.chart(start, days, width-12) + "\n")
+		if v, ok := m.rolling(m.today); ok {
+			b.WriteString("\n" + tr("Now: %s", trendMetrics[m.metric].format(v)) + "\n")
+		}
+	}
+	back := keys.Close
+	back.SetHelp("esc", "back")
+	b.WriteString("\n" + noticeStyle.Render(helpLine(keys.Metric, keys.Window, back)))
+	return b.String()
+}
+
+// runTrends shows the trends screen on its own.
+func runTrends() error {
+	s, cfg, err := openUserGames()
+	if err != nil {
+		return err
+	}
+	setLocale(cfg.Locale)
+	setTheme(cfg.Theme)
+	_, err = runViews(newTrendsModel(s, time.Now()))
+	return err
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Metadata keys for caching the update check.
+const (
+	metaUpdateCheckedOn = "update_check.checked_on"
+	metaLatestVersion   = "update_check.latest_version"
+)
+
+const releasesEndpoint = "https://api.github.com/repos/a-poor/brack/releases/latest"
+
+// checksumsAsset is the name of the release asset listing the SHA-256
+// checksums of the other assets, in sha256sum format.
+const checksumsAsset = "checksums.txt"
+
+type release struct {
+	TagName string         `json:"tag_name"`
+	HTMLURL string         `json:"html_url"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+func getLatestRelease() (release, error) {
+	slog.Debug("checking for latest release", "url", releasesEndpoint)
+	resp, err := getPolitely(releasesEndpoint)
+	if err != nil {
+		return release{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return release{}, fmt.Errorf("checking for latest release: %s", resp.Status)
+	}
+
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return release{}, err
+	}
+	return r, nil
+}
+
+// asset returns the release asset with the given name.
+func (r release) asset(name string) (releaseAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// binaryAssetName is the name of the release asset for the current
+// platform, e.g. "brack_linux_amd64" or "brack_windows_amd64.exe".
+func binaryAssetName() string {
+	name := "brack_" + runtime.GOOS + "_" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// compareVersions compares two dotted version strings (with or without
+// a leading "v"), returning -1, 0 or 1.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func download(url string) ([]byte, error) {
+	slog.Debug("downloading", "url", url)
+
+	// A release takes longer to download than a puzzle
+	c := *httpClient
+	c.Timeout = 5 * time.Minute
+	resp, err := c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// lookupChecksum finds the checksum for the named file in a
+// sha256sum-formatted checksum list.
+func lookupChecksum(sums []byte, name string) (string, bool) {
+	sc := bufio.NewScanner(bytes.NewReader(sums))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], true
+		}
+	}
+	return "", false
+}
+
+func runUpgrade(w io.Writer, checkOnly bool) error {
+	r, err := getLatestRelease()
+	if err != nil {
+		return err
+	}
+
+	// Anything to do?
+	if compareVersions(r.TagName, version) <= 0 {
+		fmt.Fprintf(w, "brack is up to date (v%s)\n", version)
+		return nil
+	}
+	fmt.Fprintf(w, "A new version of brack is available: %s (you have v%s)\n", r.TagName, version)
+	if checkOnly {
+		fmt.Fprintf(w, "Run `brack upgrade` to install it, or see %s\n", r.HTMLURL)
+		return nil
+	}
+
+	// Find the binary for this platform, and its checksum
+	name := binaryAssetName()
+	bin, ok := r.asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s", r.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sumsAsset, ok := r.asset(checksumsAsset)
+	if !ok {
+		return fmt.Errorf("release %s has no %s, refusing to install an unverified binary", r.TagName, checksumsAsset)
+	}
+	sums, err := download(sumsAsset.URL)
+	if err != nil {
+		return err
+	}
+	want, ok := lookupChecksum(sums, name)
+	if !ok {
+		return fmt.Errorf("no checksum for %s in %s", name, checksumsAsset)
+	}
+
+	// Download and verify it
+	fmt.Fprintf(w, "Downloading %s...\n", name)
+	b, err := download(bin.URL)
+	if err != nil {
+		return err
+	}
+	got := sha256.Sum256(b)
+	if hex.EncodeToString(got[:]) != strings.ToLower(want) {
+		return errors.New("checksum mismatch for downloaded binary, aborting upgrade")
+	}
+
+	// Swap it in place
+	if err := replaceExecutable(b); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Upgraded brack to %s\n", r.TagName)
+	return nil
+}
+
+// replaceExecutable replaces the running executable with b.
+func replaceExecutable(b []byte) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+
+	// Write the new binary next to the old one, so the
+	// rename below stays on the same filesystem
+	tmp := exe + ".new"
+	if err := os.WriteFile(tmp, b, 0o755); err != nil {
+		return err
+	}
+
+	// Windows won't let a running executable be overwritten,
+	// but it can be moved out of the way
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, exe); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// updateCheckMsg reports the latest released version of brack.
+type updateCheckMsg struct {
+	latest string
+	cached bool
+	err    error
+}
+
+// checkForUpdate checks for a newer release in the background, at most
+// once a day (using the result cached in the store otherwise). It
+// returns nil if update checks are disabled.
+func (m model) checkForUpdate() tea.Cmd {
+	if m.store == nil || !m.cfg.CheckForUpdates || os.Getenv("BRACK_NO_UPDATE_CHECK") != "" {
+		return nil
+	}
+
+	// Already checked today?
+	if m.store.meta(metaUpdateCheckedOn) == time.Now().Format(time.DateOnly) {
+		latest := m.store.meta(metaLatestVersion)
+		return func() tea.Msg {
+			return updateCheckMsg{latest: latest, cached: true}
+		}
+	}
+	return func() tea.Msg {
+		r, err := getLatestRelease()
+		return updateCheckMsg{latest: r.TagName, err: err}
+	}
+}
+
+// handleUpdateCheck records the result of an update check and whether
+// there's a newer version to tell the user about.
+func (m model) handleUpdateCheck(msg updateCheckMsg) model {
+	if !msg.cached {
+		// Only check once a day, even if it failed
+		if err := m.store.setMeta(metaUpdateCheckedOn, time.Now().Format(time.DateOnly)); err != nil {
+			slog.Error("failed to save update check", "err", err)
+		}
+		if msg.err != nil {
+			slog.Warn("update check failed", "err", msg.err)
+			return m
+		}
+		if err := m.store.setMeta(metaLatestVersion, msg.latest); err != nil {
+			slog.Error("failed to save update check", "err", err)
+		}
+	}
+	if msg.latest != "" && compareVersions(msg.latest, version) > 0 {
+		m.newVersion = msg.latest
+	}
+	return m
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// warmupDate stands in for the date of a warm-up puzzle, which isn't
+// saved.
+const warmupDate = "warm-up"
+
+// solvedClues returns the clues (with their answers) from the puzzles
+// the player has solved, sorted by clue.
+func solvedClues(s *store) [][2]string {
+	var clues [][2]string
+	for _, p := range s.cachedPuzzles() {
+		gs, ok := s.game(p.PuzzleDate)
+		if !ok || !gs.Done || gs.GaveUp {
+			continue
+		}
+		for clue, answer := range p.Solutions {
+			clues = append(clues, [2]string{clue, answer})
+		}
+	}
+	slices.SortFunc(clues, func(a, b [2]string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return clues
+}
+
+// warmupPuzzle makes a tiny puzzle of two or three clues the player has
+// solved before, to play as a warm-up.
+func warmupPuzzle(s *store) (puzzledata, error) {
+	clues := solvedClues(s)
+	rng.Shuffle(len(clues), func(i, j int) {
+		clues[i], clues[j] = clues[j], clues[i]
+	})
+
+	// Take two or three clues with different answers
+	n := 2 + rng.IntN(2)
+	p := puzzledata{
+		PuzzleDate:     warmupDate,
+		Solutions:      make(map[string]string),
+		CompletionText: tr("Warmed up! Press %s to play today's puzzle.", "t"),
+	}
+	var initial, solution []string
+	seen := make(map[string]bool)
+	for _, c := range clues {
+		if len(initial) == n {
+			break
+		}
+		if seen[strings.ToLower(c[1])] {
+			continue
+		}
+		seen[strings.ToLower(c[1])] = true
+		p.Solutions[c[0]] = c[1]
+		initial = append(initial, "["+c[0]+"]")
+		solution = append(solution, c[1])
+	}
+	if len(initial) < 2 {
+		return puzzledata{}, errors.New("there aren't enough solved clues for a warm-up yet, solve a puzzle first")
+	}
+	p.InitialPuzzle = tr("Warm up: %s.", strings.Join(initial, " · "))
+	p.PuzzleSolution = tr("Warm up: %s.", strings.Join(solution, " · "))
+	return p, nil
+}
+
+// runWarmup plays a warm-up puzzle. Nothing about it is saved, but the
+// player can go on to today's puzzle from the results screen.
+func runWarmup() error {
+	cfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	setLocale(cfg.Locale)
+	s, err := openUserStore()
+	if err != nil {
+		return err
+	}
+	p, err := warmupPuzzle(s)
+	if err != nil {
+		return err
+	}
+	m := newModel(p, s, cfg)
+	m.warmup = true
+	_, err = runViews(newSession(m))
+	return err
+}
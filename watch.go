@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchInterval is how often the game checks whether its puzzle's
+// progress has been changed in the database by something else, such
+// as another brack or a sync client pulling it from another machine.
+const watchInterval = 2 * time.Second
+
+// diskGame reads a date's game from the database file, if the file has
+// changed since the store last read or wrote it (or since seen, when
+// it was last checked).
+func (s *store) diskGame(date string, seen time.Time) (gs gamestate, ok bool, mod time.Time) {
+	fi, err := os.Stat(s.path)
+	if err != nil || fi.ModTime().Equal(s.modTime) || fi.ModTime().Equal(seen) {
+		return gs, false, seen
+	}
+	disk, err := readStoreFile(s.path)
+	if err != nil {
+		return gs, false, fi.ModTime()
+	}
+	gs, ok = disk.Games[date]
+	return gs, ok, fi.ModTime()
+}
+
+// sameProgress reports whether two saves of a game are at the same
+// point, ignoring the time spent.
+func sameProgress(a, b gamestate) bool {
+	return a.State == b.State && a.Correct == b.Correct && a.Incorrect == b.Incorrect &&
+		a.Chars == b.Chars && a.Done == b.Done && a.GaveUp == b.GaveUp
+}
+
+// watchStore checks (at most every watchInterval) whether the puzzle's
+// progress has been changed outside the game, and offers to load it.
+// While another brack has the puzzle open, it's loaded straight away.
+func (m model) watchStore() (model, tea.Cmd) {
+	if m.shared != nil {
+		return m.watchShared(false)
+	}
+	if m.store == nil || m.warmup || m.archived || m.dialog != nil || noSave || time.Since(m.watchedAt) < watchInterval {
+		return m, nil
+	}
+	m.watchedAt = time.Now()
+	gs, ok, mod := m.store.diskGame(m.data.PuzzleDate, m.diskMod)
+	m.diskMod = mod
+	if ours, _ := m.store.game(m.data.PuzzleDate); !ok || sameProgress(gs, ours) {
+		return m, nil
+	}
+
+	reload := func(m model) (model, tea.Cmd) {
+		m.store.data.Games[m.data.PuzzleDate] = gs
+		n, cmd := m.switchPuzzle(m.data, false)
+		n.watchedAt, n.diskMod = m.watchedAt, m.diskMod
+		return n, cmd
+	}
+	if m.lockedBy != "" {
+		return reload(m)
+	}
+	m.dialog = &dialog{
+		question: tr("This puzzle was played elsewhere (%d solved there, %d here). Load that progress?", gs.Correct, m.correct),
+		choices:  []string{tr("Load it"), tr("Keep mine")},
+		focus:    1,
+		choose: func(m model, i int) (model, tea.Cmd) {
+			if i != 0 {
+				return m, nil
+			}
+			return reload(m)
+		},
+	}
+	return m, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// webhookPayload is posted to the webhook when a puzzle is solved.
+type webhookPayload struct {
+	Date     string `json:"date"`
+	Seconds  int64  `json:"time_seconds"`
+	Errors   int    `json:"errors"`
+	Letters  int    `json:"letters"`
+	Assisted bool   `json:"assisted"`
+	Hints    int    `json:"hints_used"`
+
+	// Rank is where this solve's time places among all of the
+	// player's solves (1 is their fastest ever), or 0 if it wasn't
+	// timed.
+	Rank int `json:"rank"`
+}
+
+// webhookMsg reports the result of posting to the webhook.
+type webhookMsg struct {
+	err error
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// solveRank is where a solve's time places among all the solves in
+// the store.
+func solveRank(s *store, gs gamestate) int {
+	if gs.ElapsedSeconds == 0 {
+		return 0
+	}
+	rank := 1
+	for _, other := range s.allGames() {
+		if other.Done && !other.GaveUp && other.ElapsedSeconds > 0 && other.ElapsedSeconds < gs.ElapsedSeconds {
+			rank++
+		}
+	}
+	return rank
+}
+
+// postWebhook posts the payload to the webhook URL as JSON.
+func postWebhook(url string, p webhookPayload) tea.Cmd {
+	return func() tea.Msg {
+		body, err := json.Marshal(p)
+		if err != nil {
+			return webhookMsg{err: err}
+		}
+		resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			slog.Error("failed to post webhook", "url", url, "err", err)
+			return webhookMsg{err: err}
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			slog.Error("webhook failed", "url", url, "status", resp.Status)
+			return webhookMsg{err: fmt.Errorf("%s", resp.Status)}
+		}
+		slog.Debug("posted webhook", "url", url)
+		return webhookMsg{}
+	}
+}
+
+// notifyWebhook posts the solved game to the configured webhook, if
+// there is one.
+func (m model) notifyWebhook() tea.Cmd {
+	if m.cfg.WebhookURL == "" || m.warmup || noSave || m.host != nil {
+		return nil
+	}
+	gs := m.gamestate()
+	p := webhookPayload{
+		Date:     gs.Date,
+		Seconds:  gs.ElapsedSeconds,
+		Errors:   gs.Incorrect,
+		Letters:  gs.Chars,
+		Assisted: gs.Assisted,
+		Hints:    gs.HintsUsed,
+	}
+	if m.store != nil {
+		p.Rank = solveRank(m.store, gs)
+	}
+	return postWebhook(m.cfg.WebhookURL, p)
+}